@@ -0,0 +1,82 @@
+// Package describer 把 github.com/andyrestart9/private-repo 包在一個本地的介面後面。
+//
+// private-repo 是私有倉庫，沒有權限的人連 go build ./... 都會失敗。
+// 沒有權限的時候改用 repo 裡的替身模組 044-mod-get-private-repo/privatestub，
+// 用 go.work 把它換進來，程式碼完全不用改：
+//
+//	GOWORK=$PWD/offline.work go build ./...
+//
+// 執行時用 Active 看連結進來的是真的 private-repo 還是替身。
+package describer
+
+import (
+	"fmt"
+	"runtime/debug"
+	"strings"
+)
+
+// ModulePath is the module this package describes.
+const ModulePath = "github.com/andyrestart9/private-repo"
+
+// Describer is what our packages need from private-repo.
+type Describer interface {
+	Desc() string
+}
+
+// Func adapts a plain function such as privateRepo.Desc to Describer.
+type Func func() string
+
+func (f Func) Desc() string { return f() }
+
+// Implementation describes which private-repo was linked into the binary.
+type Implementation struct {
+	Path    string // 模組路徑，通常就是 ModulePath
+	Version string // go.mod 要求的版本
+	// Replace 不是空字串表示被 replace 換掉了，例如 go.work 的替身
+	Replace string
+	// Linked 為 false 表示執行檔裡根本沒有這個模組，或是讀不到 build info
+	Linked bool
+}
+
+// Stub reports whether the linked module is a local replacement rather
+// than the real private repository.
+func (i Implementation) Stub() bool {
+	return i.Replace != ""
+}
+
+func (i Implementation) String() string {
+	switch {
+	case !i.Linked:
+		return i.Path + " (not linked)"
+	case i.Stub():
+		return fmt.Sprintf("%s %s => %s (stub)", i.Path, i.Version, i.Replace)
+	default:
+		return i.Path + " " + i.Version
+	}
+}
+
+// Active reports which private-repo the running binary was built with.
+func Active() Implementation {
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return Implementation{Path: ModulePath}
+	}
+	return FromBuildInfo(bi, ModulePath)
+}
+
+// FromBuildInfo looks up path in the dependencies recorded in bi.
+func FromBuildInfo(bi *debug.BuildInfo, path string) Implementation {
+	impl := Implementation{Path: path}
+	for _, m := range bi.Deps {
+		if m.Path != path {
+			continue
+		}
+		impl.Version, impl.Linked = m.Version, true
+		if r := m.Replace; r != nil {
+			// replace 到本地目錄時沒有版本，只留路徑
+			impl.Replace = strings.TrimSpace(r.Path + " " + r.Version)
+		}
+		break
+	}
+	return impl
+}
@@ -0,0 +1,87 @@
+package describer
+
+import (
+	"fmt"
+	"runtime/debug"
+	"testing"
+)
+
+func TestFunc(t *testing.T) {
+	var d Describer = Func(func() string { return "desc" })
+	if x := d.Desc(); x != "desc" {
+		t.Error("Expected", "desc", "Got", x)
+	}
+}
+
+func TestFromBuildInfo(t *testing.T) {
+	const version = "v0.0.0-20250215133011-b87f94999c98"
+	other := &debug.Module{Path: "github.com/andyrestart9/puppy", Version: "v1.3.0"}
+
+	type test struct {
+		deps   []*debug.Module
+		answer Implementation
+		stub   bool
+		text   string
+	}
+	tests := []test{
+		{
+			[]*debug.Module{other},
+			Implementation{Path: ModulePath},
+			false,
+			ModulePath + " (not linked)",
+		},
+		{
+			[]*debug.Module{other, {Path: ModulePath, Version: version}},
+			Implementation{Path: ModulePath, Version: version, Linked: true},
+			false,
+			ModulePath + " " + version,
+		},
+		{
+			[]*debug.Module{{Path: ModulePath, Version: version, Replace: &debug.Module{Path: "./044-mod-get-private-repo/privatestub", Version: "(devel)"}}},
+			Implementation{Path: ModulePath, Version: version, Replace: "./044-mod-get-private-repo/privatestub (devel)", Linked: true},
+			true,
+			ModulePath + " " + version + " => ./044-mod-get-private-repo/privatestub (devel) (stub)",
+		},
+		{
+			[]*debug.Module{{Path: ModulePath, Version: version, Replace: &debug.Module{Path: "example.com/fork", Version: "v1.0.0"}}},
+			Implementation{Path: ModulePath, Version: version, Replace: "example.com/fork v1.0.0", Linked: true},
+			true,
+			ModulePath + " " + version + " => example.com/fork v1.0.0 (stub)",
+		},
+	}
+
+	for _, v := range tests {
+		x := FromBuildInfo(&debug.BuildInfo{Deps: v.deps}, ModulePath)
+		if x != v.answer {
+			t.Error("Expected", v.answer, "Got", x)
+		}
+		if x.Stub() != v.stub {
+			t.Error("Expected Stub", v.stub, "Got", x.Stub())
+		}
+		if x.String() != v.text {
+			t.Error("Expected", v.text, "Got", x.String())
+		}
+	}
+}
+
+// 測試執行檔沒有連結 private-repo
+func TestActive(t *testing.T) {
+	x := Active()
+	if x.Linked || x.Path != ModulePath {
+		t.Error("Expected", ModulePath, "not linked, Got", x)
+	}
+}
+
+func ExampleFromBuildInfo() {
+	bi := &debug.BuildInfo{Deps: []*debug.Module{{
+		Path:    ModulePath,
+		Version: "v0.0.0-20250215133011-b87f94999c98",
+		Replace: &debug.Module{Path: "./044-mod-get-private-repo/privatestub"},
+	}}}
+	impl := FromBuildInfo(bi, ModulePath)
+	fmt.Println(impl.Stub())
+	fmt.Println(impl)
+	// Output:
+	// true
+	// github.com/andyrestart9/private-repo v0.0.0-20250215133011-b87f94999c98 => ./044-mod-get-private-repo/privatestub (stub)
+}
@@ -0,0 +1,15 @@
+// Package privaterepo 是 describer.Describer 接到 private-repo 模組的實作。
+//
+// 有權限的時候連結的是真正的 github.com/andyrestart9/private-repo；
+// 用 offline.work 建置時連結的是 privatestub，這個套件不用改。
+package privaterepo
+
+import (
+	"github.com/andyrestart9/animalPackage/044-mod-get-private-repo/describer"
+	privateRepo "github.com/andyrestart9/private-repo"
+)
+
+// New returns a Describer backed by the linked private-repo.
+func New() describer.Describer {
+	return describer.Func(privateRepo.Desc)
+}
@@ -2,7 +2,9 @@ package main
 
 import (
 	"fmt"
-	privateRepo "github.com/andyrestart9/private-repo"
+
+	"github.com/andyrestart9/animalPackage/044-mod-get-private-repo/describer"
+	"github.com/andyrestart9/animalPackage/044-mod-get-private-repo/describer/privaterepo"
 )
 
 func main() {
@@ -11,5 +13,7 @@ func main() {
 	// go mod tidy
 	// 多個私有倉庫可以在同一個 GOPRIVATE 裡逗號分隔時，中間不要加空格。例如：export GOPRIVATE=git.company.com/*,github.com/your-org/*,gitlab.com/anotherteam/repo
 
-	fmt.Println(privateRepo.Desc())
+	// 沒有權限的話用 repo 裡的替身：GOWORK=$PWD/offline.work go run ./044-mod-get-private-repo
+	fmt.Println(privaterepo.New().Desc())
+	fmt.Println("using", describer.Active())
 }
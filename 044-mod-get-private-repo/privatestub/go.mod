@@ -0,0 +1,3 @@
+module github.com/andyrestart9/private-repo
+
+go 1.23.6
@@ -3,19 +3,23 @@ package main
 import (
 	"fmt"
 
-	"github.com/andyrestart9/puppy"
+	"github.com/andyrestart9/animalPackage/049-specifiying-dependnecy-version/barker/puppyadapter"
 )
 
 // go get github.com/andyrestart9/puppy
 // go mod tidy
+//
+// puppy 的呼叫都包在 barker.Barker 後面，puppy 升版時只要改 puppyadapter
 func main() {
-	s1 := puppy.Bark()
-	s2 := puppy.Barks()
+	b := puppyadapter.New()
+
+	s1 := b.Bark()
+	s2 := b.Barks()
 
 	fmt.Println(s1)
 	fmt.Println(s2)
 
 	// also like this
-	fmt.Println(puppy.Bark())
-	fmt.Println(puppy.Barks())
+	fmt.Println(b.Bark())
+	fmt.Println(b.Barks())
 }
@@ -3,25 +3,31 @@ package main
 import (
 	"fmt"
 
-	"github.com/andyrestart9/puppy"
+	"github.com/andyrestart9/animalPackage/049-specifiying-dependnecy-version/barker"
+	"github.com/andyrestart9/animalPackage/049-specifiying-dependnecy-version/barker/puppyadapter"
 )
 
 // go get github.com/andyrestart9/puppy
 // go mod tidy
+//
+// puppy 的呼叫都包在 barker.Barker 後面，puppy 升版時只要改 puppyadapter
 func main() {
-	s1 := puppy.Bark()
-	s2 := puppy.Barks()
+	b := puppyadapter.New()
+
+	s1 := b.Bark()
+	s2 := b.Barks()
 
 	fmt.Println(s1)
 	fmt.Println(s2)
 
-	s3 := puppy.BidBark()
-	s4 := puppy.BidBarks()
+	// 舊版 puppy 沒有 BidBark，BigBarkOrBark 會退回 Bark
+	s3 := barker.BigBarkOrBark(b)
+	s4 := barker.BigBarksOrBarks(b)
 
 	fmt.Println(s3)
 	fmt.Println(s4)
 
 	// also like this
-	fmt.Println(puppy.Bark())
-	fmt.Println(puppy.Barks())
+	fmt.Println(b.Bark())
+	fmt.Println(b.Barks())
 }
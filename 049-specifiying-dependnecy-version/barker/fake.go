@@ -0,0 +1,81 @@
+package barker
+
+// Fake is an in-memory Barker for tests. The zero value behaves like a
+// puppy without big barks; set the fields to change what it returns.
+type Fake struct {
+	Ver       string // 預設 "fake"
+	BarkText  string // 預設 "Woof!"
+	BarksText string // 預設 "Woof! Woof! Woof!"
+
+	// BigBarkText 不是空字串時才支援 BigBark，模擬新版的 puppy
+	BigBarkText  string
+	BigBarksText string
+	HasFrom13    bool
+
+	// Calls records every method called, in order.
+	Calls []string
+}
+
+var _ Barker = (*Fake)(nil)
+
+// Version returns Ver, or "fake" if it is empty.
+func (f *Fake) Version() string {
+	if f.Ver == "" {
+		return "fake"
+	}
+	return f.Ver
+}
+
+// Bark returns BarkText, or "Woof!" if it is empty.
+func (f *Fake) Bark() string {
+	f.Calls = append(f.Calls, "Bark")
+	return or(f.BarkText, "Woof!")
+}
+
+// Barks returns BarksText, or "Woof! Woof! Woof!" if it is empty.
+func (f *Fake) Barks() string {
+	f.Calls = append(f.Calls, "Barks")
+	return or(f.BarksText, "Woof! Woof! Woof!")
+}
+
+// SupportsBigBark reports whether BigBarkText is set.
+func (f *Fake) SupportsBigBark() bool { return f.BigBarkText != "" }
+
+// BigBark returns BigBarkText, or an error wrapping ErrUnsupported
+// if big barks are not supported.
+func (f *Fake) BigBark() (string, error) {
+	f.Calls = append(f.Calls, "BigBark")
+	if !f.SupportsBigBark() {
+		return "", Unsupported("BigBark", f.Version())
+	}
+	return f.BigBarkText, nil
+}
+
+// BigBarks returns BigBarksText, falling back to BigBarkText, or an
+// error wrapping ErrUnsupported if big barks are not supported.
+func (f *Fake) BigBarks() (string, error) {
+	f.Calls = append(f.Calls, "BigBarks")
+	if !f.SupportsBigBark() {
+		return "", Unsupported("BigBarks", f.Version())
+	}
+	return or(f.BigBarksText, f.BigBarkText), nil
+}
+
+// SupportsFrom13 reports HasFrom13.
+func (f *Fake) SupportsFrom13() bool { return f.HasFrom13 }
+
+// From13 returns an error wrapping ErrUnsupported unless HasFrom13 is set.
+func (f *Fake) From13() error {
+	f.Calls = append(f.Calls, "From13")
+	if !f.HasFrom13 {
+		return Unsupported("From13", f.Version())
+	}
+	return nil
+}
+
+func or(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
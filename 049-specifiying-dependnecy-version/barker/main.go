@@ -0,0 +1,65 @@
+// Package barker 把 github.com/andyrestart9/puppy 包在一個本地的介面後面。
+//
+// 045、046、049 原本直接呼叫 puppy.Bark、puppy.BidBark、puppy.From13，
+// puppy 一升版，每個 main 都要跟著改。現在 main 只認識 Barker：
+//   - 真正的實作在 barker/puppyadapter，依 build tag 對應不同的 puppy 版本
+//   - 測試用 Fake，不需要下載 puppy
+//
+// 舊版 puppy 沒有 BidBark 和 From13，呼叫端先用 SupportsBigBark、SupportsFrom13 問一下，
+// 或是直接處理 ErrUnsupported。
+package barker
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnsupported is returned by methods the linked puppy version lacks.
+var ErrUnsupported = errors.New("barker: not supported by this puppy version")
+
+// Barker is what our packages need from puppy.
+//
+// puppy 把 BigBark 拼成 BidBark，這裡用正確的拼法，只有 adapter 需要知道 puppy 的名字。
+type Barker interface {
+	// Version is the puppy module version the implementation wraps.
+	Version() string
+
+	Bark() string
+	Barks() string
+
+	// SupportsBigBark reports whether BigBark and BigBarks work.
+	SupportsBigBark() bool
+	BigBark() (string, error)
+	BigBarks() (string, error)
+
+	// SupportsFrom13 reports whether From13 works (puppy v1.3.0 and later).
+	SupportsFrom13() bool
+	From13() error
+}
+
+// BigBarkOrBark returns b.BigBark(), or b.Bark() when big barks are not
+// supported, so callers degrade gracefully on older puppy versions.
+func BigBarkOrBark(b Barker) string {
+	if b.SupportsBigBark() {
+		if s, err := b.BigBark(); err == nil {
+			return s
+		}
+	}
+	return b.Bark()
+}
+
+// BigBarksOrBarks is like BigBarkOrBark for BigBarks and Barks.
+func BigBarksOrBarks(b Barker) string {
+	if b.SupportsBigBark() {
+		if s, err := b.BigBarks(); err == nil {
+			return s
+		}
+	}
+	return b.Barks()
+}
+
+// Unsupported returns an error wrapping ErrUnsupported.
+// 把方法名稱和版本放進錯誤訊息裡
+func Unsupported(method, version string) error {
+	return fmt.Errorf("%w: %s (puppy %s)", ErrUnsupported, method, version)
+}
@@ -0,0 +1,65 @@
+package barker
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestFakeOldPuppy(t *testing.T) {
+	f := &Fake{Ver: "v1.0.0"}
+	if f.SupportsBigBark() || f.SupportsFrom13() {
+		t.Error("an old puppy should not support BigBark or From13")
+	}
+	if _, err := f.BigBark(); !errors.Is(err, ErrUnsupported) {
+		t.Error("got", err, "want", ErrUnsupported)
+	}
+	if _, err := f.BigBarks(); !errors.Is(err, ErrUnsupported) {
+		t.Error("got", err, "want", ErrUnsupported)
+	}
+	if err := f.From13(); !errors.Is(err, ErrUnsupported) {
+		t.Error("got", err, "want", ErrUnsupported)
+	}
+}
+
+func TestDegrade(t *testing.T) {
+	type test struct {
+		b     *Fake
+		big   string
+		bigs  string
+		calls []string
+	}
+	tests := []test{
+		{&Fake{}, "Woof!", "Woof! Woof! Woof!", []string{"Bark", "Barks"}},
+		{&Fake{BigBarkText: "WOOF!", BigBarksText: "WOOF! WOOF!"}, "WOOF!", "WOOF! WOOF!", []string{"BigBark", "BigBarks"}},
+	}
+	for _, v := range tests {
+		if x := BigBarkOrBark(v.b); x != v.big {
+			t.Error("Expected", v.big, "Got", x)
+		}
+		if x := BigBarksOrBarks(v.b); x != v.bigs {
+			t.Error("Expected", v.bigs, "Got", x)
+		}
+		// 不支援時根本不該呼叫 BigBark
+		if !slices.Equal(v.b.Calls, v.calls) {
+			t.Error("Expected calls", v.calls, "Got", v.b.Calls)
+		}
+	}
+}
+
+func ExampleBigBarkOrBark() {
+	old := &Fake{Ver: "v1.0.0"}
+	cur := &Fake{Ver: "v1.3.0", BigBarkText: "WOOF!", HasFrom13: true}
+
+	for _, b := range []Barker{old, cur} {
+		fmt.Println(b.Version(), BigBarkOrBark(b))
+		if err := b.From13(); err != nil {
+			fmt.Println(err)
+		}
+	}
+	// Output:
+	// v1.0.0 Woof!
+	// barker: not supported by this puppy version: From13 (puppy v1.0.0)
+	// v1.3.0 WOOF!
+}
@@ -0,0 +1,56 @@
+//go:build !puppy_legacy
+
+// Package puppyadapter 是 barker.Barker 接到真正 puppy 模組的實作。
+//
+// 預設對應 go.mod 裡的 github.com/andyrestart9/puppy v1.3.0。
+// 如果要連結沒有 BidBark、From13 的舊版 puppy，用 puppy_legacy 這個 build tag：
+//
+//	go get github.com/andyrestart9/puppy@v1.0.0
+//	go build -tags puppy_legacy ./...
+//
+// 換版本只會動到這個套件，用到 barker.Barker 的 main 都不用改。
+package puppyadapter
+
+import (
+	"github.com/andyrestart9/animalPackage/049-specifiying-dependnecy-version/barker"
+	"github.com/andyrestart9/puppy"
+)
+
+// Version is the puppy version this file is written against.
+const Version = "v1.3.0"
+
+// PuppyV13 adapts puppy v1.3.0 to barker.Barker.
+type PuppyV13 struct{}
+
+var _ barker.Barker = PuppyV13{}
+
+// New returns the adapter for the linked puppy version.
+func New() barker.Barker { return PuppyV13{} }
+
+// Version returns Version.
+func (PuppyV13) Version() string { return Version }
+
+// Bark calls puppy.Bark.
+func (PuppyV13) Bark() string { return puppy.Bark() }
+
+// Barks calls puppy.Barks.
+func (PuppyV13) Barks() string { return puppy.Barks() }
+
+// SupportsBigBark reports true: v1.3.0 has BidBark.
+func (PuppyV13) SupportsBigBark() bool { return true }
+
+// BigBark calls puppy.BidBark and never fails.
+// puppy 的拼法是 BidBark
+func (PuppyV13) BigBark() (string, error) { return puppy.BidBark(), nil }
+
+// BigBarks calls puppy.BidBarks and never fails.
+func (PuppyV13) BigBarks() (string, error) { return puppy.BidBarks(), nil }
+
+// SupportsFrom13 reports true: v1.3.0 has From13.
+func (PuppyV13) SupportsFrom13() bool { return true }
+
+// From13 calls puppy.From13 and never fails.
+func (PuppyV13) From13() error {
+	puppy.From13()
+	return nil
+}
@@ -0,0 +1,44 @@
+//go:build puppy_legacy
+
+package puppyadapter
+
+import (
+	"github.com/andyrestart9/animalPackage/049-specifiying-dependnecy-version/barker"
+	"github.com/andyrestart9/puppy"
+)
+
+// Version describes the puppy versions this file is written against:
+// the releases from before BidBark was added.
+const Version = "legacy"
+
+// PuppyLegacy adapts puppy versions that only have Bark and Barks.
+type PuppyLegacy struct{}
+
+var _ barker.Barker = PuppyLegacy{}
+
+// New returns the adapter for the linked puppy version.
+func New() barker.Barker { return PuppyLegacy{} }
+
+// Version returns Version.
+func (PuppyLegacy) Version() string { return Version }
+
+// Bark calls puppy.Bark.
+func (PuppyLegacy) Bark() string { return puppy.Bark() }
+
+// Barks calls puppy.Barks.
+func (PuppyLegacy) Barks() string { return puppy.Barks() }
+
+// SupportsBigBark reports false: legacy puppy has no BidBark.
+func (PuppyLegacy) SupportsBigBark() bool { return false }
+
+// BigBark always returns an error wrapping barker.ErrUnsupported.
+func (PuppyLegacy) BigBark() (string, error) { return "", barker.Unsupported("BigBark", Version) }
+
+// BigBarks always returns an error wrapping barker.ErrUnsupported.
+func (PuppyLegacy) BigBarks() (string, error) { return "", barker.Unsupported("BigBarks", Version) }
+
+// SupportsFrom13 reports false: From13 was added in v1.3.0.
+func (PuppyLegacy) SupportsFrom13() bool { return false }
+
+// From13 always returns an error wrapping barker.ErrUnsupported.
+func (PuppyLegacy) From13() error { return barker.Unsupported("From13", Version) }
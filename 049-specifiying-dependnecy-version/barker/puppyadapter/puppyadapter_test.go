@@ -0,0 +1,35 @@
+package puppyadapter
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/andyrestart9/animalPackage/049-specifiying-dependnecy-version/barker"
+)
+
+// 沒有 build tag，預設和 puppy_legacy 兩種建置都會跑：
+// Supports 回報的能力要和方法實際的結果一致
+func TestCapabilities(t *testing.T) {
+	b := New()
+	if b.Version() != Version {
+		t.Error("Expected", Version, "Got", b.Version())
+	}
+	if b.Bark() == "" || b.Barks() == "" {
+		t.Error("Expected Bark and Barks to say something")
+	}
+
+	check := func(name string, supported bool, err error) {
+		t.Helper()
+		if supported && err != nil {
+			t.Error(name, "Expected <nil> Got", err)
+		}
+		if !supported && !errors.Is(err, barker.ErrUnsupported) {
+			t.Error(name, "Expected", barker.ErrUnsupported, "Got", err)
+		}
+	}
+	_, err := b.BigBark()
+	check("BigBark", b.SupportsBigBark(), err)
+	_, err = b.BigBarks()
+	check("BigBarks", b.SupportsBigBark(), err)
+	check("From13", b.SupportsFrom13(), b.From13())
+}
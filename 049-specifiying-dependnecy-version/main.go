@@ -3,26 +3,36 @@ package main
 import (
 	"fmt"
 
-	"github.com/andyrestart9/puppy"
+	"github.com/andyrestart9/animalPackage/049-specifiying-dependnecy-version/barker"
+	"github.com/andyrestart9/animalPackage/049-specifiying-dependnecy-version/barker/puppyadapter"
 )
+
 // go get github.com/andyrestart9/puppy@v1.3.0
 // go mod tidy
+//
+// puppyadapter 依 build tag 對應 puppy 的版本，預設是 v1.3.0；
+// 連結舊版 puppy 時用 go build -tags puppy_legacy
 func main() {
-	puppy.From13()
+	b := puppyadapter.New()
+	fmt.Println("puppy", b.Version())
+
+	if b.SupportsFrom13() {
+		b.From13()
+	}
 
-	s1 := puppy.Bark()
-	s2 := puppy.Barks()
+	s1 := b.Bark()
+	s2 := b.Barks()
 
 	fmt.Println(s1)
 	fmt.Println(s2)
 
-	s3 := puppy.BidBark()
-	s4 := puppy.BidBarks()
+	s3 := barker.BigBarkOrBark(b)
+	s4 := barker.BigBarksOrBarks(b)
 
 	fmt.Println(s3)
 	fmt.Println(s4)
 
 	// also like this
-	fmt.Println(puppy.Bark())
-	fmt.Println(puppy.Barks())
+	fmt.Println(b.Bark())
+	fmt.Println(b.Barks())
 }
@@ -0,0 +1,147 @@
+// Package dispatch 取代 Announce(v any) 裡寫死的 type switch。
+//
+// 原本每多一個型別就要改 switch，遇到不認識的型別還會 panic。
+// Dispatcher 依照下面的順序找出怎麼讓 v 說話：
+//
+//  1. v 實作了 Speaker
+//  2. v 的 reflect.Type 有註冊 Handler
+//  3. v 實作了 fmt.Stringer
+//  4. 都沒有就回傳 *UnsupportedTypeError
+//
+// 新型別只要在執行期註冊 Handler，不用改任何 switch。
+package dispatch
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+)
+
+// Speaker is the preferred capability: anything that can speak itself.
+type Speaker interface {
+	Speak() string
+}
+
+// Handler makes v speak. v always has the type the handler was registered for.
+type Handler func(v any) (string, error)
+
+// ErrUnsupportedType matches every *UnsupportedTypeError with errors.Is.
+var ErrUnsupportedType = errors.New("dispatch: unsupported type")
+
+// ErrNilPointer is returned for a nil pointer whose Speak or String
+// method has a value receiver and would therefore panic.
+var ErrNilPointer = errors.New("dispatch: nil pointer")
+
+// UnsupportedTypeError is returned when no capability applies to a value.
+type UnsupportedTypeError struct {
+	Type reflect.Type // nil 表示傳入的是 nil
+}
+
+func (e *UnsupportedTypeError) Error() string {
+	return fmt.Sprintf("dispatch: unsupported type %v", e.Type)
+}
+
+// Is reports whether target is ErrUnsupportedType.
+func (e *UnsupportedTypeError) Is(target error) bool {
+	return target == ErrUnsupportedType
+}
+
+// Dispatcher holds the handlers registered per type. It is safe for
+// concurrent use, and the zero value is an empty Dispatcher ready to use.
+type Dispatcher struct {
+	mu       sync.RWMutex
+	handlers map[reflect.Type]Handler
+}
+
+// New returns a Dispatcher with no handlers.
+func New() *Dispatcher {
+	return &Dispatcher{handlers: make(map[reflect.Type]Handler)}
+}
+
+// Handle registers h for values whose dynamic type is exactly t,
+// replacing any previous handler for t.
+func (d *Dispatcher) Handle(t reflect.Type, h Handler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	// 零值的 Dispatcher 還沒有 map，第一次註冊時才建立
+	if d.handlers == nil {
+		d.handlers = make(map[reflect.Type]Handler)
+	}
+	d.handlers[t] = h
+}
+
+// Unhandle removes the handler for t.
+func (d *Dispatcher) Unhandle(t reflect.Type) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.handlers, t)
+}
+
+// HandleFunc registers f for values of type T on d.
+//
+// 用泛型包一層，呼叫端不用自己寫 reflect.TypeOf 和型別斷言：
+//
+//	dispatch.HandleFunc(d, func(dg Dog) string { return "Woof, " + dg.Name })
+func HandleFunc[T any](d *Dispatcher, f func(T) string) {
+	d.Handle(reflect.TypeFor[T](), func(v any) (string, error) {
+		return f(v.(T)), nil
+	})
+}
+
+// Speak returns what v says, trying each capability in priority order.
+func (d *Dispatcher) Speak(v any) (string, error) {
+	if s, ok := v.(Speaker); ok {
+		if nilValueReceiver[Speaker](v) {
+			return "", fmt.Errorf("%w: %T", ErrNilPointer, v)
+		}
+		return s.Speak(), nil
+	}
+
+	t := reflect.TypeOf(v)
+	d.mu.RLock()
+	h, ok := d.handlers[t]
+	d.mu.RUnlock()
+	if ok {
+		return h(v)
+	}
+
+	if s, ok := v.(fmt.Stringer); ok {
+		if nilValueReceiver[fmt.Stringer](v) {
+			return "", fmt.Errorf("%w: %T", ErrNilPointer, v)
+		}
+		return s.String(), nil
+	}
+	return "", &UnsupportedTypeError{Type: t}
+}
+
+// nilValueReceiver 回報 v 是不是 nil 指標，而且 I 的方法是值接收器：
+// *T 的方法集包含 T 的方法，但呼叫時要先 *v，nil 指標就會 panic。
+// 指標接收器的方法可以自己處理 nil，所以不算。
+func nilValueReceiver[I any](v any) bool {
+	rv := reflect.ValueOf(v)
+	return rv.Kind() == reflect.Pointer && rv.IsNil() && rv.Type().Elem().Implements(reflect.TypeFor[I]())
+}
+
+// Announce writes what v says to w.
+func (d *Dispatcher) Announce(w io.Writer, v any) error {
+	s, err := d.Speak(v)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, s)
+	return err
+}
+
+// Default is the Dispatcher used by the package-level functions.
+var Default = New()
+
+// Handle registers h for t on Default.
+func Handle(t reflect.Type, h Handler) { Default.Handle(t, h) }
+
+// Speak returns what v says using Default.
+func Speak(v any) (string, error) { return Default.Speak(v) }
+
+// Announce writes what v says to w using Default.
+func Announce(w io.Writer, v any) error { return Default.Announce(w, v) }
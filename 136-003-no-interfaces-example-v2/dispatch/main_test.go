@@ -0,0 +1,142 @@
+package dispatch
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+type human struct{ name string }
+
+func (h human) Speak() string { return "Hi, I'm " + h.name }
+
+// both 同時實作 Speaker 和 fmt.Stringer，用來檢查優先順序
+type both struct{}
+
+func (both) Speak() string  { return "speaker" }
+func (both) String() string { return "stringer" }
+
+type dog struct{ name string }
+
+type temperature float64
+
+func (t temperature) String() string { return fmt.Sprintf("%.1f°C", float64(t)) }
+
+func TestPriority(t *testing.T) {
+	d := New()
+	HandleFunc(d, func(v dog) string { return "Woof! I'm " + v.name })
+	HandleFunc(d, func(v both) string { return "handler" })
+	HandleFunc(d, func(v temperature) string { return "handler" })
+	HandleFunc(d, func(v int) string { return "int " + strconv.Itoa(v) })
+
+	type test struct {
+		v      any
+		answer string
+	}
+	tests := []test{
+		{human{"Andy"}, "Hi, I'm Andy"},
+		{both{}, "speaker"}, // Speaker 優先於 Handler
+		{dog{"Buddy"}, "Woof! I'm Buddy"},
+		{temperature(21.5), "handler"}, // Handler 優先於 Stringer
+		{7, "int 7"},
+	}
+	for _, v := range tests {
+		x, err := d.Speak(v.v)
+		if err != nil || x != v.answer {
+			t.Errorf("Speak(%#v): Expected %q Got %q, %v", v.v, v.answer, x, err)
+		}
+	}
+
+	// 沒有 Handler 時才輪到 Stringer
+	d.Unhandle(reflect.TypeFor[temperature]())
+	if x, _ := d.Speak(temperature(21.5)); x != "21.5°C" {
+		t.Error("Expected", "21.5°C", "Got", x)
+	}
+}
+
+func TestUnsupported(t *testing.T) {
+	d := New()
+	for _, v := range []any{42, "hi", dog{"Buddy"}, &dog{"Buddy"}, nil} {
+		_, err := d.Speak(v)
+		if !errors.Is(err, ErrUnsupportedType) {
+			t.Errorf("Speak(%#v): got %v want ErrUnsupportedType", v, err)
+			continue
+		}
+		var ute *UnsupportedTypeError
+		if !errors.As(err, &ute) || ute.Type != reflect.TypeOf(v) {
+			t.Errorf("Speak(%#v): got %v want *UnsupportedTypeError for %v", v, err, reflect.TypeOf(v))
+		}
+	}
+}
+
+// 指標型別的方法集包含值接收器的方法，所以 *human 也是 Speaker
+func TestPointerSpeaker(t *testing.T) {
+	x, err := New().Speak(&human{"Andy"})
+	if err != nil || x != "Hi, I'm Andy" {
+		t.Error("Expected", "Hi, I'm Andy", "Got", x, err)
+	}
+}
+
+// nilSpeaker 的 Speak 是指標接收器，可以自己處理 nil
+type nilSpeaker struct{}
+
+func (s *nilSpeaker) Speak() string {
+	if s == nil {
+		return "nobody"
+	}
+	return "somebody"
+}
+
+// 值接收器的方法遇到 nil 指標會 panic，要回傳錯誤
+func TestNilPointer(t *testing.T) {
+	d := New()
+	for _, v := range []any{(*human)(nil), (*temperature)(nil)} {
+		if x, err := d.Speak(v); !errors.Is(err, ErrNilPointer) {
+			t.Errorf("Speak(%#v): Expected ErrNilPointer Got %q, %v", v, x, err)
+		}
+	}
+	if x, err := d.Speak((*nilSpeaker)(nil)); err != nil || x != "nobody" {
+		t.Error("Expected nobody Got", x, err)
+	}
+}
+
+func TestHandlerError(t *testing.T) {
+	d := New()
+	boom := errors.New("boom")
+	d.Handle(reflect.TypeFor[dog](), func(v any) (string, error) { return "", boom })
+
+	var buf bytes.Buffer
+	if err := d.Announce(&buf, dog{"Buddy"}); !errors.Is(err, boom) {
+		t.Error("got", err, "want", boom)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("nothing should be written, got %q", buf.String())
+	}
+}
+
+func TestZeroValue(t *testing.T) {
+	var d Dispatcher
+	d.Unhandle(reflect.TypeFor[dog]())
+	if _, err := d.Speak(dog{"Buddy"}); !errors.Is(err, ErrUnsupportedType) {
+		t.Error("Expected", ErrUnsupportedType, "Got", err)
+	}
+	HandleFunc(&d, func(v dog) string { return "Woof! I'm " + v.name })
+	if x, err := d.Speak(dog{"Buddy"}); err != nil || x != "Woof! I'm Buddy" {
+		t.Error("Expected Woof! I'm Buddy Got", x, err)
+	}
+}
+
+func ExampleHandleFunc() {
+	d := New()
+	HandleFunc(d, func(v dog) string { return "Woof! I'm " + v.name })
+
+	fmt.Println(d.Speak(dog{"Buddy"}))
+	_, err := d.Speak(42)
+	fmt.Println(err)
+	// Output:
+	// Woof! I'm Buddy <nil>
+	// dispatch: unsupported type int
+}
@@ -1,25 +1,50 @@
 package main
 
-import "fmt"
+import (
+	"fmt"
+	"os"
+
+	"github.com/andyrestart9/animalPackage/136-003-no-interfaces-example-v2/dispatch"
+)
 
 type Human struct{ Name string }
+
 func (h Human) Speak() string { return "Hi, I'm " + h.Name }
 
 type Robot struct{ ID int }
+
 func (r Robot) Speak() string { return fmt.Sprintf("Beep! I am robot #%d", r.ID) }
 
-func Announce(v any) {           // 接受空介面
-	switch s := v.(type) {       // 逐一列舉型別
-	case Human:
-		fmt.Println(s.Speak())
-	case Robot:
-		fmt.Println(s.Speak())
-	default:
-		panic("unsupported type")
+// Dog 沒有 Speak 方法，也沒有 String 方法
+type Dog struct{ Name string }
+
+// 原本的寫法：逐一列舉型別，多一個型別就要改 switch，不認識的型別直接 panic
+//
+//	func Announce(v any) {           // 接受空介面
+//		switch s := v.(type) {       // 逐一列舉型別
+//		case Human:
+//			fmt.Println(s.Speak())
+//		case Robot:
+//			fmt.Println(s.Speak())
+//		default:
+//			panic("unsupported type")
+//		}
+//	}
+//
+// 現在交給 dispatch：先看 Speaker 介面，再看註冊的 Handler，再看 fmt.Stringer，
+// 最後回傳錯誤而不是 panic
+func Announce(v any) {
+	if err := dispatch.Announce(os.Stdout, v); err != nil {
+		fmt.Println("error:", err)
 	}
 }
 
 func main() {
+	// Dog 在執行期註冊，不用改 Announce
+	dispatch.HandleFunc(dispatch.Default, func(d Dog) string { return "Woof! I'm " + d.Name })
+
 	Announce(Human{"Andy"})
 	Announce(Robot{42})
-}
\ No newline at end of file
+	Announce(Dog{"Buddy"})
+	Announce(42) // error: dispatch: unsupported type int
+}
@@ -1,28 +1,35 @@
 package main
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+	"os"
 
-// 只需要「說話」能力，所以介面就留一個方法
-type Speaker interface {
-	Speak() string
-}
-
-// 高階函式：跟誰說話都可以，只要能 Speak
-func Announce(s Speaker) {
-	fmt.Println(s.Speak())
-}
-
-// --------- 不同實作，各自滿足 Speaker ---------
+	"github.com/andyrestart9/animalPackage/136-004-change-example-to-interface-version/speaker"
+)
 
-type Human struct{ Name string }
+// Speaker 介面、Human、Robot、Announce 都搬到 speaker 套件裡了
 
-func (h Human) Speak() string { return "Hi, I'm " + h.Name }
-
-type Robot struct{ ID int }
-
-func (r Robot) Speak() string { return fmt.Sprintf("Beep! I am robot #%d", r.ID) }
+// 從設定檔讀進來的內容，不用重新編譯就能換成別的 Speaker
+const config = `[
+	{"type": "human", "name": "Andy"},
+	{"type": "robot", "id": 42}
+]`
 
 func main() {
-	Announce(Human{"Andy"})   // Hi, I'm Andy
-	Announce(Robot{42})       // Beep! I am robot #42
-}
\ No newline at end of file
+	// 跟原本一樣手動建構
+	speaker.Announce(os.Stdout, speaker.Human{Name: "Andy"}) // Hi, I'm Andy
+	speaker.Announce(os.Stdout, speaker.Robot{ID: 42})       // Beep! I am robot #42
+
+	// 用 registry 從 JSON 建構
+	ss, err := speaker.FromJSON([]byte(config))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	speaker.Announce(os.Stdout, ss...)
+
+	// 每次呼叫用 context 決定語系，訊息來自 speaker/messages 裡嵌入的樣板
+	ctx := speaker.WithLocale(context.Background(), "zh-TW")
+	speaker.AnnounceContext(ctx, os.Stdout, ss...) // 嗨，我是 Andy / 嗶！我是 42 號機器人
+}
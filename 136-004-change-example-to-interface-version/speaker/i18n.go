@@ -0,0 +1,142 @@
+package speaker
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+// 每個語系一個檔案，檔名就是語系，例如 messages/zh-TW.tmpl。
+// 檔案裡每則訊息用 {{define "key"}}...{{end}} 定義，資料是 Speaker 本身。
+//
+//go:embed messages/*.tmpl
+var messageFS embed.FS
+
+// FallbackLocale is used when the requested locale has no catalog or no
+// template for a key.
+const FallbackLocale = "en"
+
+// Messages is the catalog used by Human and Robot.
+var Messages = mustLoadCatalog(messageFS, "messages", FallbackLocale)
+
+// ErrNoMessage is returned when neither the locale nor the fallback
+// defines a key.
+var ErrNoMessage = errors.New("speaker: no message")
+
+// Catalog holds one template set per locale.
+type Catalog struct {
+	fallback string
+	locales  map[string]*template.Template // key 是正規化過的語系，例如 "zh-tw"
+}
+
+// LoadCatalog parses every *.tmpl file in dir of fsys; the file name
+// without extension is the locale. fallback must be one of them.
+func LoadCatalog(fsys fs.FS, dir, fallback string) (*Catalog, error) {
+	files, err := fs.Glob(fsys, path.Join(dir, "*.tmpl"))
+	if err != nil {
+		return nil, err
+	}
+	c := &Catalog{
+		fallback: normalizeLocale(fallback),
+		locales:  make(map[string]*template.Template, len(files)),
+	}
+	for _, f := range files {
+		locale := normalizeLocale(strings.TrimSuffix(path.Base(f), ".tmpl"))
+		t, err := template.ParseFS(fsys, f)
+		if err != nil {
+			return nil, fmt.Errorf("speaker: loading catalog %s: %w", f, err)
+		}
+		c.locales[locale] = t
+	}
+	if _, ok := c.locales[c.fallback]; !ok {
+		return nil, fmt.Errorf("speaker: fallback locale %q not found in %s", fallback, dir)
+	}
+	return c, nil
+}
+
+func mustLoadCatalog(fsys fs.FS, dir, fallback string) *Catalog {
+	c, err := LoadCatalog(fsys, dir, fallback)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// Render executes the template key for the locale carried by ctx.
+//
+// 找訊息的順序：完整語系 (zh-TW) → 主要語言 (zh) → fallback 語系。
+func (c *Catalog) Render(ctx context.Context, key string, data any) (string, error) {
+	for _, locale := range c.candidates(LocaleFrom(ctx)) {
+		t := c.locales[locale].Lookup(key)
+		if t == nil {
+			continue
+		}
+		var b strings.Builder
+		if err := t.Execute(&b, data); err != nil {
+			return "", fmt.Errorf("speaker: rendering %q for %s: %w", key, locale, err)
+		}
+		return b.String(), nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrNoMessage, key)
+}
+
+// candidates 回傳有 catalog 的候選語系，依優先順序排列
+func (c *Catalog) candidates(locale string) []string {
+	locale = normalizeLocale(locale)
+	var out []string
+	add := func(l string) {
+		if _, ok := c.locales[l]; ok && !slices.Contains(out, l) {
+			out = append(out, l)
+		}
+	}
+	add(locale)
+	if base, _, ok := strings.Cut(locale, "-"); ok {
+		add(base)
+	}
+	add(c.fallback)
+	return out
+}
+
+// normalizeLocale 讓 "zh_TW"、"zh-tw"、"ZH-TW" 都對應到同一個 catalog
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(locale, "_", "-"))
+}
+
+// --------- 透過 context 傳遞語系 ---------
+
+type localeKey struct{}
+
+// WithLocale returns a copy of ctx that carries locale, e.g. "zh-TW".
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// LocaleFrom returns the locale carried by ctx, or FallbackLocale.
+func LocaleFrom(ctx context.Context) string {
+	if locale, ok := ctx.Value(localeKey{}).(string); ok && locale != "" {
+		return locale
+	}
+	return FallbackLocale
+}
+
+// LocalizedSpeaker is a Speaker that can speak in the locale carried by ctx.
+type LocalizedSpeaker interface {
+	Speaker
+	SpeakContext(ctx context.Context) string
+}
+
+// speak 找不到訊息或樣板執行失敗時回傳 key 本身，跟多數 i18n 函式庫一樣，
+// 畫面上看到 "human.speak" 就知道是哪則翻譯出了問題
+func speak(ctx context.Context, key string, data any) string {
+	s, err := Messages.Render(ctx, key, data)
+	if err != nil {
+		return key
+	}
+	return s
+}
@@ -0,0 +1,108 @@
+package speaker
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"testing/fstest"
+)
+
+func TestSpeakContext(t *testing.T) {
+	type test struct {
+		locale string
+		s      LocalizedSpeaker
+		answer string
+	}
+
+	tests := []test{
+		{"", Human{"Andy"}, "Hi, I'm Andy"},
+		{"en", Robot{42}, "Beep! I am robot #42"},
+		{"zh-TW", Human{"Andy"}, "嗨，我是 Andy"},
+		{"zh_tw", Robot{42}, "嗶！我是 42 號機器人"},
+		{"fr", Human{"Andy"}, "Hi, I'm Andy"}, // 沒有 fr，用 fallback
+	}
+
+	for _, v := range tests {
+		ctx := WithLocale(context.Background(), v.locale)
+		x := v.s.SpeakContext(ctx)
+		if x != v.answer {
+			t.Error(v.locale, "Expected", v.answer, "Got", x)
+		}
+	}
+
+	// Speak 不帶 context，等於 fallback 語系
+	if x := (Human{"Andy"}).Speak(); x != "Hi, I'm Andy" {
+		t.Error("Expected", "Hi, I'm Andy", "Got", x)
+	}
+}
+
+func TestAnnounceContext(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := WithLocale(context.Background(), "zh-TW")
+	if err := AnnounceContext(ctx, &buf, Human{"Andy"}, Robot{42}); err != nil {
+		t.Fatal(err)
+	}
+	want := "嗨，我是 Andy\n嗶！我是 42 號機器人\n"
+	if buf.String() != want {
+		t.Errorf("got %q want %q", buf.String(), want)
+	}
+}
+
+func TestCatalogFallback(t *testing.T) {
+	fsys := fstest.MapFS{
+		"msg/en.tmpl": {Data: []byte(`{{define "a"}}A{{end}}{{define "b"}}B {{.}}{{end}}`)},
+		"msg/zh.tmpl": {Data: []byte(`{{define "a"}}甲{{end}}`)},
+	}
+	c, err := LoadCatalog(fsys, "msg", "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type test struct {
+		locale string
+		key    string
+		answer string
+	}
+	tests := []test{
+		{"zh-HK", "a", "甲"},   // 沒有 zh-HK，退到主要語言 zh
+		{"zh-HK", "b", "B 1"}, // zh 沒有 b，退到 en
+		{"de", "a", "A"},
+	}
+	for _, v := range tests {
+		x, err := c.Render(WithLocale(context.Background(), v.locale), v.key, 1)
+		if err != nil || x != v.answer {
+			t.Error(v.locale, v.key, "Expected", v.answer, "Got", x, err)
+		}
+	}
+
+	if _, err := c.Render(context.Background(), "missing", nil); !errors.Is(err, ErrNoMessage) {
+		t.Error("got", err, "want", ErrNoMessage)
+	}
+}
+
+func TestLoadCatalogErrors(t *testing.T) {
+	fsys := fstest.MapFS{
+		"msg/en.tmpl": {Data: []byte(`{{define "a"}}A{{end}}`)},
+		"bad/en.tmpl": {Data: []byte(`{{define "a"}}{{.Oops{{end}}`)},
+		"none/README": {Data: []byte(`no templates here`)},
+	}
+	if _, err := LoadCatalog(fsys, "msg", "zh-TW"); err == nil {
+		t.Error("expected an error for a missing fallback locale")
+	}
+	if _, err := LoadCatalog(fsys, "bad", "en"); err == nil {
+		t.Error("expected an error for a broken template")
+	}
+	if _, err := LoadCatalog(fsys, "none", "en"); err == nil {
+		t.Error("expected an error for an empty catalog")
+	}
+}
+
+func ExampleWithLocale() {
+	ctx := WithLocale(context.Background(), "zh-TW")
+	AnnounceContext(ctx, os.Stdout, Human{"Andy"}, Robot{42})
+	// Output:
+	// 嗨，我是 Andy
+	// 嗶！我是 42 號機器人
+}
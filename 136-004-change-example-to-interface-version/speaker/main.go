@@ -0,0 +1,222 @@
+// Package speaker 把 Speaker 介面和它的實作抽成套件，並加上一個依名稱註冊實作的 registry，
+// 這樣就能從 config map 或 JSON 建出 Speaker，而不用在程式裡一個一個手動建構。
+//
+// 新的實作只要在自己套件的 init 裡註冊就好，跟 database/sql 註冊 driver 的做法一樣：
+//
+//	func init() {
+//		speaker.Register("cat", speaker.Struct[Cat]())
+//	}
+package speaker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+)
+
+// Speaker is anything that can speak.
+type Speaker interface {
+	Speak() string
+}
+
+// Announce writes what every speaker says to w, one per line.
+func Announce(w io.Writer, ss ...Speaker) error {
+	return AnnounceContext(context.Background(), w, ss...)
+}
+
+// AnnounceContext is like Announce, but a LocalizedSpeaker speaks in the
+// locale carried by ctx.
+func AnnounceContext(ctx context.Context, w io.Writer, ss ...Speaker) error {
+	for _, s := range ss {
+		var msg string
+		if ls, ok := s.(LocalizedSpeaker); ok {
+			msg = ls.SpeakContext(ctx)
+		} else {
+			msg = s.Speak()
+		}
+		if _, err := fmt.Fprintln(w, msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// --------- 內建實作 ---------
+
+// 說的話來自 Messages 的 "human.speak" 和 "robot.speak" 樣板，
+// Speak 用 fallback 語系，SpeakContext 用 ctx 帶的語系。
+
+// Human is a speaker with a name.
+type Human struct {
+	Name string `json:"name"`
+}
+
+func (h Human) Speak() string { return h.SpeakContext(context.Background()) }
+
+func (h Human) SpeakContext(ctx context.Context) string { return speak(ctx, "human.speak", h) }
+
+// Robot is a speaker with an ID.
+type Robot struct {
+	ID int `json:"id"`
+}
+
+func (r Robot) Speak() string { return r.SpeakContext(context.Background()) }
+
+func (r Robot) SpeakContext(ctx context.Context) string { return speak(ctx, "robot.speak", r) }
+
+// --------- registry ---------
+
+// TypeKey is the config key that names the registered implementation.
+const TypeKey = "type"
+
+var (
+	// ErrUnknownType is returned when no factory is registered under a name.
+	ErrUnknownType = errors.New("speaker: unknown type")
+	// ErrDuplicate is returned when a name is registered twice.
+	ErrDuplicate = errors.New("speaker: already registered")
+)
+
+// Factory builds a Speaker from its config.
+// cfg 不包含 TypeKey。
+type Factory func(cfg map[string]any) (Speaker, error)
+
+// Registry maps names to factories. It is safe for concurrent use.
+type Registry struct {
+	mu        sync.RWMutex
+	factories map[string]Factory
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{factories: make(map[string]Factory)}
+}
+
+// Register makes a factory available under name.
+func (r *Registry) Register(name string, f Factory) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.factories[name]; ok {
+		return fmt.Errorf("%w: %q", ErrDuplicate, name)
+	}
+	r.factories[name] = f
+	return nil
+}
+
+// Names returns the registered names in sorted order.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.factories))
+	for name := range r.factories {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// New builds the speaker registered under name.
+func (r *Registry) New(name string, cfg map[string]any) (Speaker, error) {
+	r.mu.RLock()
+	f, ok := r.factories[name]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownType, name)
+	}
+	s, err := f(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("speaker: building %q: %w", name, err)
+	}
+	return s, nil
+}
+
+// FromConfig builds a speaker from a config map whose TypeKey entry names
+// the implementation, e.g. {"type": "robot", "id": 42}.
+func (r *Registry) FromConfig(cfg map[string]any) (Speaker, error) {
+	name, ok := cfg[TypeKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("speaker: config is missing a string %q field", TypeKey)
+	}
+	rest := make(map[string]any, len(cfg)-1)
+	for k, v := range cfg {
+		if k != TypeKey {
+			rest[k] = v
+		}
+	}
+	return r.New(name, rest)
+}
+
+// FromJSON builds speakers from a JSON object or an array of objects,
+// each in the form accepted by FromConfig.
+func (r *Registry) FromJSON(data []byte) ([]Speaker, error) {
+	var cfgs []map[string]any
+	if err := json.Unmarshal(data, &cfgs); err != nil {
+		// 不是陣列的話，再試試看單一物件
+		var cfg map[string]any
+		if err2 := json.Unmarshal(data, &cfg); err2 != nil {
+			return nil, fmt.Errorf("speaker: decoding JSON: %w", err)
+		}
+		cfgs = []map[string]any{cfg}
+	}
+
+	ss := make([]Speaker, 0, len(cfgs))
+	for i, cfg := range cfgs {
+		s, err := r.FromConfig(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("speaker %d: %w", i, err)
+		}
+		ss = append(ss, s)
+	}
+	return ss, nil
+}
+
+// Struct returns a factory that decodes the config into a T using its
+// json struct tags. Unknown keys are an error.
+func Struct[T Speaker]() Factory {
+	return func(cfg map[string]any) (Speaker, error) {
+		// 透過 JSON 轉一次，就能直接沿用 struct tag 和型別檢查
+		data, err := json.Marshal(cfg)
+		if err != nil {
+			return nil, err
+		}
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		var s T
+		if err := dec.Decode(&s); err != nil {
+			return nil, err
+		}
+		return s, nil
+	}
+}
+
+// --------- 預設 registry ---------
+
+// Default is the registry used by the package-level functions.
+// human 和 robot 已經註冊好了。
+var Default = NewRegistry()
+
+func init() {
+	Register("human", Struct[Human]())
+	Register("robot", Struct[Robot]())
+}
+
+// Register registers f under name in Default. It panics if name is
+// already taken, because that is a programming error in an init function.
+func Register(name string, f Factory) {
+	if err := Default.Register(name, f); err != nil {
+		panic(err)
+	}
+}
+
+// New builds a speaker from Default.
+func New(name string, cfg map[string]any) (Speaker, error) { return Default.New(name, cfg) }
+
+// FromConfig builds a speaker from Default.
+func FromConfig(cfg map[string]any) (Speaker, error) { return Default.FromConfig(cfg) }
+
+// FromJSON builds speakers from Default.
+func FromJSON(data []byte) ([]Speaker, error) { return Default.FromJSON(data) }
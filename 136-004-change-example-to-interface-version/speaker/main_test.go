@@ -0,0 +1,123 @@
+package speaker
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+// cat 是測試用的第三方實作，模擬別的套件在 init 裡註冊自己
+type cat struct {
+	Name  string `json:"name"`
+	Lives int    `json:"lives"`
+}
+
+func (c cat) Speak() string { return fmt.Sprintf("Meow, %s has %d lives", c.Name, c.Lives) }
+
+func TestAnnounce(t *testing.T) {
+	var buf bytes.Buffer
+	err := Announce(&buf, Human{"Andy"}, Robot{42})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Hi, I'm Andy\nBeep! I am robot #42\n"
+	if buf.String() != want {
+		t.Errorf("got %q want %q", buf.String(), want)
+	}
+}
+
+func TestDefaultRegistry(t *testing.T) {
+	got := strings.Join(Default.Names(), ",")
+	if got != "human,robot" {
+		t.Error("got", got, "want", "human,robot")
+	}
+
+	s, err := FromConfig(map[string]any{"type": "robot", "id": 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s != (Robot{7}) {
+		t.Error("got", s, "want", Robot{7})
+	}
+}
+
+func TestRegister(t *testing.T) {
+	r := NewRegistry()
+	if err := r.Register("cat", Struct[cat]()); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Register("cat", Struct[cat]()); !errors.Is(err, ErrDuplicate) {
+		t.Error("got", err, "want", ErrDuplicate)
+	}
+
+	s, err := r.New("cat", map[string]any{"name": "Tom", "lives": 9})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Speak() != "Meow, Tom has 9 lives" {
+		t.Error("got", s.Speak())
+	}
+
+	// 自己的 registry 跟 Default 互不影響
+	if _, err := New("cat", nil); !errors.Is(err, ErrUnknownType) {
+		t.Error("got", err, "want", ErrUnknownType)
+	}
+}
+
+func TestFromJSON(t *testing.T) {
+	type test struct {
+		data   string
+		answer []Speaker
+	}
+
+	tests := []test{
+		{`{"type":"human","name":"Andy"}`, []Speaker{Human{"Andy"}}},
+		{`[{"type":"human","name":"Andy"},{"type":"robot","id":42}]`, []Speaker{Human{"Andy"}, Robot{42}}},
+		{`[]`, []Speaker{}},
+	}
+
+	for _, v := range tests {
+		ss, err := FromJSON([]byte(v.data))
+		if err != nil {
+			t.Error(v.data, "unexpected error:", err)
+			continue
+		}
+		if fmt.Sprint(ss) != fmt.Sprint(v.answer) {
+			t.Error(v.data, "Expected", v.answer, "Got", ss)
+		}
+	}
+}
+
+func TestFromJSONErrors(t *testing.T) {
+	tests := []string{
+		`not json`,
+		`{"name":"Andy"}`,
+		`{"type":"dragon"}`,
+		`{"type":"robot","id":"forty-two"}`,
+		`{"type":"human","name":"Andy","age":3}`,
+	}
+	for _, v := range tests {
+		if _, err := FromJSON([]byte(v)); err == nil {
+			t.Error(v, "expected an error")
+		}
+	}
+
+	if _, err := FromJSON([]byte(`{"type":"dragon"}`)); !errors.Is(err, ErrUnknownType) {
+		t.Error("got", err, "want", ErrUnknownType)
+	}
+}
+
+func ExampleFromJSON() {
+	ss, err := FromJSON([]byte(`[{"type":"human","name":"Andy"},{"type":"robot","id":42}]`))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	Announce(os.Stdout, ss...)
+	// Output:
+	// Hi, I'm Andy
+	// Beep! I am robot #42
+}
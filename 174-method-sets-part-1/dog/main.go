@@ -0,0 +1,221 @@
+// Package dog 是 174-method-sets-part-1 裡 dog 的完整版。
+//
+// 原本的 run() 透過指標接收器偷偷把 d.first 改成 "Rover"，呼叫端不知道，也沒有任何紀錄。
+// 這裡的 Dog 把狀態（名字、位置、體力）藏在未匯出的欄位裡，只能透過指標方法修改；
+// 每個方法都先檢查能不能做，成功之後記到歷史紀錄，並通知所有 Observer。
+package dog
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// MaxEnergy is the energy of a fully rested dog.
+const MaxEnergy = 100
+
+// Energy cost per step.
+const (
+	WalkCost = 1
+	RunCost  = 3
+)
+
+// Errors returned by Dog methods. 失敗時狀態不會改變，也不會發出 Event。
+var (
+	ErrEmptyName   = errors.New("dog: name must not be empty")
+	ErrBadDistance = errors.New("dog: distance must be positive")
+	ErrNoEnergy    = errors.New("dog: no energy left")
+	ErrTooTired    = errors.New("dog: not enough energy")
+	ErrBadAmount   = errors.New("dog: rest amount must be positive")
+)
+
+// Kind says which mutation an Event records.
+type Kind int
+
+const (
+	Renamed Kind = iota + 1
+	Walked
+	Ran
+	Rested
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Renamed:
+		return "renamed"
+	case Walked:
+		return "walked"
+	case Ran:
+		return "ran"
+	case Rested:
+		return "rested"
+	}
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+// State is a snapshot of a dog.
+type State struct {
+	Name     string
+	Position int
+	Energy   int
+}
+
+// Event records one successful mutation.
+type Event struct {
+	Kind   Kind
+	Before State
+	After  State
+}
+
+func (e Event) String() string {
+	return fmt.Sprintf("%s %s: %+v -> %+v", e.Before.Name, e.Kind, e.Before, e.After)
+}
+
+// Observer is notified after every successful mutation.
+type Observer interface {
+	OnEvent(e Event)
+}
+
+// ObserverFunc adapts a function to Observer, like http.HandlerFunc.
+type ObserverFunc func(e Event)
+
+func (f ObserverFunc) OnEvent(e Event) { f(e) }
+
+// Dog is a dog with explicit state. A Dog is not safe for concurrent use.
+type Dog struct {
+	name      string
+	position  int
+	energy    int
+	history   []Event
+	observers map[int]Observer
+	nextID    int
+}
+
+// New returns a fully rested dog at position 0.
+func New(name string) (*Dog, error) {
+	if name == "" {
+		return nil, ErrEmptyName
+	}
+	return &Dog{name: name, energy: MaxEnergy}, nil
+}
+
+func (d *Dog) Name() string  { return d.name }
+func (d *Dog) Position() int { return d.position }
+func (d *Dog) Energy() int   { return d.energy }
+
+// State returns a snapshot of d.
+func (d *Dog) State() State {
+	return State{Name: d.name, Position: d.position, Energy: d.energy}
+}
+
+// History returns a copy of every event so far, oldest first.
+func (d *Dog) History() []Event {
+	return append([]Event(nil), d.history...)
+}
+
+// Subscribe registers o and returns a function that unregisters it.
+func (d *Dog) Subscribe(o Observer) (unsubscribe func()) {
+	if d.observers == nil {
+		d.observers = make(map[int]Observer)
+	}
+	id := d.nextID
+	d.nextID++
+	d.observers[id] = o
+	return func() { delete(d.observers, id) }
+}
+
+// Rename changes the name. 原本 run() 偷偷做的事，現在要明確呼叫。
+func (d *Dog) Rename(name string) error {
+	if name == "" {
+		return ErrEmptyName
+	}
+	d.mutate(Renamed, func() { d.name = name })
+	return nil
+}
+
+// Walk moves the dog steps forward, costing WalkCost energy per step.
+func (d *Dog) Walk(steps int) error {
+	if err := d.move(steps, WalkCost); err != nil {
+		return fmt.Errorf("%s cannot walk %d steps: %w", d.name, steps, err)
+	}
+	d.mutate(Walked, func() {
+		d.position += steps
+		d.energy -= steps * WalkCost
+	})
+	return nil
+}
+
+// Run moves the dog steps forward, costing RunCost energy per step.
+// A dog with zero energy refuses to run.
+func (d *Dog) Run(steps int) error {
+	if err := d.move(steps, RunCost); err != nil {
+		return fmt.Errorf("%s cannot run %d steps: %w", d.name, steps, err)
+	}
+	d.mutate(Ran, func() {
+		d.position += steps
+		d.energy -= steps * RunCost
+	})
+	return nil
+}
+
+// Rest restores energy, up to MaxEnergy. Resting at full energy
+// changes nothing and records no event.
+func (d *Dog) Rest(amount int) error {
+	if amount <= 0 {
+		return ErrBadAmount
+	}
+	if d.energy == MaxEnergy {
+		return nil
+	}
+	d.mutate(Rested, func() {
+		// 先比較再加，d.energy+amount 在 amount 很大時會溢位
+		if amount >= MaxEnergy-d.energy {
+			d.energy = MaxEnergy
+		} else {
+			d.energy += amount
+		}
+	})
+	return nil
+}
+
+// move 檢查能不能走或跑 steps 步，不會改變任何狀態
+func (d *Dog) move(steps, cost int) error {
+	switch {
+	case steps <= 0:
+		return ErrBadDistance
+	case d.energy == 0:
+		return ErrNoEnergy
+	// 用除法比較，steps*cost 在 steps 很大時會溢位變成負數
+	case steps > d.energy/cost:
+		if steps > math.MaxInt/cost {
+			return fmt.Errorf("%w: need more than %d, have %d", ErrTooTired, math.MaxInt, d.energy)
+		}
+		return fmt.Errorf("%w: need %d, have %d", ErrTooTired, steps*cost, d.energy)
+	}
+	return nil
+}
+
+// mutate 執行 f，記錄前後狀態，再通知 observer
+func (d *Dog) mutate(kind Kind, f func()) {
+	before := d.State()
+	f()
+	e := Event{Kind: kind, Before: before, After: d.State()}
+	d.history = append(d.history, e)
+	for _, id := range d.observerIDs() {
+		// observer 可能在 OnEvent 裡取消訂閱別人，所以每次都重新確認還在不在
+		if o, ok := d.observers[id]; ok {
+			o.OnEvent(e)
+		}
+	}
+}
+
+// observerIDs 依訂閱順序回傳 observer 的 id
+func (d *Dog) observerIDs() []int {
+	ids := make([]int, 0, len(d.observers))
+	for id := 0; id < d.nextID; id++ {
+		if _, ok := d.observers[id]; ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,133 @@
+package dog
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	if _, err := New(""); !errors.Is(err, ErrEmptyName) {
+		t.Error("got", err, "want", ErrEmptyName)
+	}
+	d, err := New("Henry")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := State{Name: "Henry", Position: 0, Energy: MaxEnergy}
+	if d.State() != want {
+		t.Error("Expected", want, "Got", d.State())
+	}
+}
+
+func TestMoves(t *testing.T) {
+	d, _ := New("Henry")
+
+	type test struct {
+		name  string
+		do    func() error
+		err   error
+		state State
+	}
+	tests := []test{
+		{"walk 10", func() error { return d.Walk(10) }, nil, State{"Henry", 10, 90}},
+		{"run 20", func() error { return d.Run(20) }, nil, State{"Henry", 30, 30}},
+		{"walk 0", func() error { return d.Walk(0) }, ErrBadDistance, State{"Henry", 30, 30}},
+		{"run -1", func() error { return d.Run(-1) }, ErrBadDistance, State{"Henry", 30, 30}},
+		{"run 11", func() error { return d.Run(11) }, ErrTooTired, State{"Henry", 30, 30}},
+		{"run overflow", func() error { return d.Run(math.MaxInt/2 + 1) }, ErrTooTired, State{"Henry", 30, 30}},
+		{"walk MaxInt", func() error { return d.Walk(math.MaxInt) }, ErrTooTired, State{"Henry", 30, 30}},
+		{"run 10", func() error { return d.Run(10) }, nil, State{"Henry", 40, 0}},
+		{"run at zero", func() error { return d.Run(1) }, ErrNoEnergy, State{"Henry", 40, 0}},
+		{"walk at zero", func() error { return d.Walk(1) }, ErrNoEnergy, State{"Henry", 40, 0}},
+		{"rest 0", func() error { return d.Rest(0) }, ErrBadAmount, State{"Henry", 40, 0}},
+		{"rest 500", func() error { return d.Rest(500) }, nil, State{"Henry", 40, MaxEnergy}},
+		{"rename empty", func() error { return d.Rename("") }, ErrEmptyName, State{"Henry", 40, MaxEnergy}},
+		{"rename", func() error { return d.Rename("Rover") }, nil, State{"Rover", 40, MaxEnergy}},
+		{"rest when full", func() error { return d.Rest(5) }, nil, State{"Rover", 40, MaxEnergy}},
+		{"walk 5", func() error { return d.Walk(5) }, nil, State{"Rover", 45, 95}},
+		{"rest MaxInt", func() error { return d.Rest(math.MaxInt) }, nil, State{"Rover", 45, MaxEnergy}},
+	}
+
+	for _, v := range tests {
+		err := v.do()
+		if !errors.Is(err, v.err) {
+			t.Error(v.name, "Expected error", v.err, "Got", err)
+		}
+		if d.State() != v.state {
+			t.Error(v.name, "Expected", v.state, "Got", d.State())
+		}
+	}
+
+	// 只有成功而且有改變狀態的那幾次會留下紀錄
+	var kinds []Kind
+	for _, e := range d.History() {
+		kinds = append(kinds, e.Kind)
+	}
+	want := fmt.Sprint([]Kind{Walked, Ran, Ran, Rested, Renamed, Walked, Rested})
+	if fmt.Sprint(kinds) != want {
+		t.Error("Expected", want, "Got", kinds)
+	}
+}
+
+func TestHistoryIsACopy(t *testing.T) {
+	d, _ := New("Henry")
+	d.Walk(1)
+	h := d.History()
+	h[0].Kind = Ran
+	if d.History()[0].Kind != Walked {
+		t.Error("History must return a copy")
+	}
+}
+
+func TestObservers(t *testing.T) {
+	d, _ := New("Henry")
+
+	var a, b []Event
+	unsubA := d.Subscribe(ObserverFunc(func(e Event) { a = append(a, e) }))
+	d.Subscribe(ObserverFunc(func(e Event) { b = append(b, e) }))
+
+	d.Walk(5)
+	d.Run(1000) // 失敗，不會通知
+	unsubA()
+	d.Rest(1)
+
+	if len(a) != 1 || len(b) != 2 {
+		t.Fatal("Expected 1 and 2 events Got", len(a), len(b))
+	}
+	want := Event{Walked, State{"Henry", 0, 100}, State{"Henry", 5, 95}}
+	if a[0] != want {
+		t.Error("Expected", want, "Got", a[0])
+	}
+	if b[1].Kind != Rested || b[1].After.Energy != 96 {
+		t.Error("Expected a rest to 96 Got", b[1])
+	}
+}
+
+// 在 OnEvent 裡取消訂閱另一個 observer，被取消的那個這次就不會收到
+func TestUnsubscribeDuringEvent(t *testing.T) {
+	d, _ := New("Henry")
+	var unsubB func()
+	calls := 0
+	d.Subscribe(ObserverFunc(func(Event) { unsubB() }))
+	unsubB = d.Subscribe(ObserverFunc(func(Event) { calls++ }))
+
+	d.Walk(1)
+	if calls != 0 {
+		t.Error("Expected", 0, "Got", calls)
+	}
+}
+
+func ExampleDog_Run() {
+	d, _ := New("Henry")
+	d.Subscribe(ObserverFunc(func(e Event) { fmt.Println(e) }))
+
+	d.Run(30)
+	if err := d.Run(5); err != nil {
+		fmt.Println(err)
+	}
+	// Output:
+	// Henry ran: {Name:Henry Position:0 Energy:100} -> {Name:Henry Position:30 Energy:10}
+	// Henry cannot run 5 steps: dog: not enough energy: need 15, have 10
+}
@@ -1,6 +1,10 @@
 package main
 
-import "fmt"
+import (
+	"fmt"
+
+	pet "github.com/andyrestart9/animalPackage/174-method-sets-part-1/dog"
+)
 
 type dog struct {
 	first string
@@ -24,10 +28,19 @@ func main() {
 	d1.walk()
 	d1.run()
 
-
 	d2 := &dog{"Padget"}
 	d2.walk()
 	d2.run()
+
+	// dog 套件的版本（import 時取名 pet，避免跟上面的 type dog 撞名）：改名要明確呼叫 Rename，每次狀態改變都會通知 observer
+	d3, _ := pet.New("Henry")
+	d3.Subscribe(pet.ObserverFunc(func(e pet.Event) { fmt.Println("event:", e) }))
+	d3.Walk(10)
+	d3.Run(30)
+	if err := d3.Run(1); err != nil {
+		fmt.Println(err) // 體力是 0，拒絕再跑
+	}
+	d3.Rename("Rover")
 }
 
 // Go指針接收器的自動解引用機制
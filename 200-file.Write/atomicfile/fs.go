@@ -0,0 +1,72 @@
+package atomicfile
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"runtime"
+)
+
+// FS is the part of the file system a Writer touches. OS is the real one;
+// tests substitute a fake to simulate failures.
+type FS interface {
+	Stat(name string) (fs.FileInfo, error)
+	// Lstat 和 Readlink 用来把符号链接解析到真正的目标文件
+	Lstat(name string) (fs.FileInfo, error)
+	Readlink(name string) (string, error)
+	// Open 只在不能建立硬链接、要复制旧文件当备份时使用
+	Open(name string) (io.ReadCloser, error)
+	// CreateTemp 跟 os.CreateTemp 一样，在 dir 里建立一个新的临时文件
+	CreateTemp(dir, pattern string) (File, error)
+	Rename(oldpath, newpath string) error
+	Remove(name string) error
+	Link(oldname, newname string) error
+	// SyncDir 把目录本身刷到磁盘，rename 之后不做这一步，断电后新的目录项可能会丢失
+	SyncDir(dir string) error
+}
+
+// File is an open temporary file.
+type File interface {
+	Name() string
+	Write(p []byte) (int, error)
+	Chmod(mode fs.FileMode) error
+	Sync() error
+	Close() error
+}
+
+// OS is the FS backed by package os.
+var OS FS = osFS{}
+
+type osFS struct{}
+
+func (osFS) Stat(name string) (fs.FileInfo, error) { return os.Stat(name) }
+
+func (osFS) Lstat(name string) (fs.FileInfo, error) { return os.Lstat(name) }
+
+func (osFS) Readlink(name string) (string, error) { return os.Readlink(name) }
+
+func (osFS) Open(name string) (io.ReadCloser, error) { return os.Open(name) }
+
+func (osFS) CreateTemp(dir, pattern string) (File, error) { return os.CreateTemp(dir, pattern) }
+
+func (osFS) Rename(oldpath, newpath string) error { return os.Rename(oldpath, newpath) }
+
+func (osFS) Remove(name string) error { return os.Remove(name) }
+
+func (osFS) Link(oldname, newname string) error { return os.Link(oldname, newname) }
+
+func (osFS) SyncDir(dir string) error {
+	// Windows 不能打开目录来 Sync，rename 本身已经是持久的
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	err = d.Sync()
+	if cerr := d.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
@@ -0,0 +1,263 @@
+// Package atomicfile writes files so that readers see either the old
+// content or the new content, never a truncated mix.
+//
+// 200-file.Write 的例子直接 os.Create 目标文件再 Write，写到一半崩溃就只剩半个文件，
+// 而且 defer file.Close() 把 Close 的错误丢掉了（很多文件系统到 Close 才回报写入失败）。
+// 这里的做法是：
+//
+//  1. 在同一个目录建立临时文件（同一个文件系统，rename 才是原子的）
+//  2. 写入、设定权限、fsync
+//  3. 需要的话，把旧文件硬链接成备份（文件系统不支持硬链接时改成复制）
+//  4. rename 覆盖目标文件
+//  5. fsync 目录，让 rename 本身也落盘
+//
+// 任何一步失败都会删除临时文件，目标文件保持原样。
+//
+// name 是符号链接时，替换的是链接最终指向的文件，链接本身保持不变。
+package atomicfile
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"path/filepath"
+)
+
+// DefaultPerm is the permission of a new file when Options.Perm is zero.
+// 跟 os.Create 不同，这里不会再套用 umask。
+const DefaultPerm fs.FileMode = 0o644
+
+// ErrClosed is returned by Write after Close or Abort.
+var ErrClosed = errors.New("atomicfile: writer already closed")
+
+// maxLinks 是解析符号链接时最多跟几层，跟 Linux 的 ELOOP 上限差不多
+const maxLinks = 40
+
+// Options configures a Writer. The zero value is ready to use.
+type Options struct {
+	// Perm 是新文件的权限。目标文件已经存在时保留它原来的权限，不看 Perm
+	Perm fs.FileMode
+	// BackupSuffix 不是空字符串时，覆盖前把旧文件保留成 name+BackupSuffix，例如 ".bak"
+	BackupSuffix string
+	// FS 是 nil 时使用 OS
+	FS FS
+}
+
+// Writer writes to a temporary file and replaces the target on Close.
+type Writer struct {
+	name string
+	opts Options
+	fsys FS
+	tmp  File
+	perm fs.FileMode
+	// err 是第一个写入错误，有错误时 Close 不会替换目标文件
+	err    error
+	closed bool
+}
+
+// Create starts writing name. Nothing is visible at name until Close
+// succeeds. If name is a symbolic link, the file it points to is replaced
+// and the link is kept. opts may be nil.
+func Create(name string, opts *Options) (*Writer, error) {
+	w := &Writer{}
+	if opts != nil {
+		w.opts = *opts
+	}
+	w.fsys = w.opts.FS
+	if w.fsys == nil {
+		w.fsys = OS
+	}
+	// rename 会把符号链接本身换成普通文件，所以先找到真正的目标，临时文件也建在目标的目录里
+	name, err := resolve(w.fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	w.name = name
+
+	w.perm = w.opts.Perm
+	if w.perm == 0 {
+		w.perm = DefaultPerm
+	}
+	if fi, err := w.fsys.Stat(name); err == nil {
+		if !fi.Mode().IsRegular() {
+			return nil, &fs.PathError{Op: "atomicfile", Path: name, Err: errors.New("not a regular file")}
+		}
+		w.perm = fi.Mode().Perm()
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	dir, base := filepath.Split(name)
+	if dir == "" {
+		dir = "."
+	}
+	tmp, err := w.fsys.CreateTemp(dir, "."+base+".tmp-*")
+	if err != nil {
+		return nil, err
+	}
+	w.tmp = tmp
+	return w, nil
+}
+
+// resolve 跟着符号链接找到最终的路径；最后指向不存在的文件也没关系，那就是要建立的文件
+func resolve(fsys FS, name string) (string, error) {
+	for range maxLinks {
+		fi, err := fsys.Lstat(name)
+		if errors.Is(err, fs.ErrNotExist) {
+			return name, nil
+		}
+		if err != nil {
+			return "", err
+		}
+		if fi.Mode()&fs.ModeSymlink == 0 {
+			return name, nil
+		}
+		target, err := fsys.Readlink(name)
+		if err != nil {
+			return "", err
+		}
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(filepath.Dir(name), target)
+		}
+		name = target
+	}
+	return "", &fs.PathError{Op: "atomicfile", Path: name, Err: errors.New("too many levels of symbolic links")}
+}
+
+// Write writes p to the temporary file. After a failed Write, Close
+// discards the file instead of committing it.
+func (w *Writer) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, ErrClosed
+	}
+	if w.err != nil {
+		return 0, w.err
+	}
+	n, err := w.tmp.Write(p)
+	if err != nil {
+		w.err = err
+	}
+	return n, err
+}
+
+// Close syncs the temporary file and renames it over the target. The
+// returned error joins every failure along the way, including the
+// write error that prevented the commit.
+func (w *Writer) Close() error {
+	if w.closed {
+		return ErrClosed
+	}
+	w.closed = true
+
+	err := w.err
+	if err == nil {
+		err = w.tmp.Chmod(w.perm)
+	}
+	if err == nil {
+		err = w.tmp.Sync()
+	}
+	// 不管前面有没有失败都要 Close，Close 的错误也要回报
+	if cerr := w.tmp.Close(); cerr != nil {
+		err = errors.Join(err, cerr)
+	}
+	if err != nil {
+		return w.cleanup(err)
+	}
+
+	if w.opts.BackupSuffix != "" {
+		if err := w.backup(); err != nil {
+			return w.cleanup(err)
+		}
+	}
+	if err := w.fsys.Rename(w.tmp.Name(), w.name); err != nil {
+		return w.cleanup(err)
+	}
+	// 到这里目标文件已经换掉了，只是还不保证断电后还在，所以不删除任何东西
+	return w.fsys.SyncDir(filepath.Dir(w.name))
+}
+
+// Abort discards everything written so far and leaves the target
+// untouched. Abort after Close is a no-op.
+func (w *Writer) Abort() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	return w.cleanup(w.tmp.Close())
+}
+
+// backup 用硬链接保留旧文件：旧文件始终存在，不会有目标文件短暂消失的空窗
+func (w *Writer) backup() error {
+	bak := w.name + w.opts.BackupSuffix
+	if _, err := w.fsys.Stat(w.name); errors.Is(err, fs.ErrNotExist) {
+		return nil // 第一次写入，没有旧文件
+	}
+	if err := w.fsys.Remove(bak); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	lerr := w.fsys.Link(w.name, bak)
+	if lerr == nil {
+		return nil
+	}
+	// 有些文件系统（FAT、部分网络文件系统）不支持硬链接，改成复制
+	if err := w.copyBackup(bak); err != nil {
+		return errors.Join(lerr, err)
+	}
+	return nil
+}
+
+// copyBackup 把旧文件复制成 bak，一样先写临时文件再 rename，备份也不会只有一半
+func (w *Writer) copyBackup(bak string) (err error) {
+	src, err := w.fsys.Open(w.name)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dir, base := filepath.Split(bak)
+	if dir == "" {
+		dir = "."
+	}
+	tmp, err := w.fsys.CreateTemp(dir, "."+base+".tmp-*")
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(tmp, src)
+	if err == nil {
+		err = tmp.Chmod(w.perm)
+	}
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if cerr := tmp.Close(); cerr != nil {
+		err = errors.Join(err, cerr)
+	}
+	if err == nil {
+		err = w.fsys.Rename(tmp.Name(), bak)
+	}
+	if err != nil {
+		if rerr := w.fsys.Remove(tmp.Name()); rerr != nil && !errors.Is(rerr, fs.ErrNotExist) {
+			err = errors.Join(err, rerr)
+		}
+	}
+	return err
+}
+
+// cleanup 删除临时文件，把删除的错误也一起回报
+func (w *Writer) cleanup(err error) error {
+	if rerr := w.fsys.Remove(w.tmp.Name()); rerr != nil && !errors.Is(rerr, fs.ErrNotExist) {
+		err = errors.Join(err, rerr)
+	}
+	return err
+}
+
+// WriteFile atomically replaces name with data. opts may be nil.
+func WriteFile(name string, data []byte, opts *Options) error {
+	w, err := Create(name, opts)
+	if err != nil {
+		return err
+	}
+	// Write 的错误会记在 w 里，由 Close 一起回报
+	w.Write(data)
+	return w.Close()
+}
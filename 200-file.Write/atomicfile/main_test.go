@@ -0,0 +1,448 @@
+package atomicfile
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// memFS 是测试用的内存文件系统，fail 里的操作会回传对应的错误
+type memFS struct {
+	files map[string]*memNode
+	fail  map[string]error // 操作名称 -> 错误，例如 "write"、"rename"
+	ops   []string
+	seq   int
+}
+
+type memNode struct {
+	data   []byte
+	mode   fs.FileMode
+	synced bool
+}
+
+func newMemFS() *memFS {
+	return &memFS{files: map[string]*memNode{}, fail: map[string]error{}}
+}
+
+func (m *memFS) op(name string) error {
+	m.ops = append(m.ops, name)
+	return m.fail[name]
+}
+
+func (m *memFS) Stat(name string) (fs.FileInfo, error) {
+	n, ok := m.files[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+	}
+	return memInfo{name: filepath.Base(name), node: n}, nil
+}
+
+func (m *memFS) Lstat(name string) (fs.FileInfo, error) { return m.Stat(name) }
+
+// 符号链接的 memNode：mode 带 fs.ModeSymlink，data 是链接的目标
+func (m *memFS) Readlink(name string) (string, error) {
+	n, ok := m.files[name]
+	if !ok || n.mode&fs.ModeSymlink == 0 {
+		return "", &fs.PathError{Op: "readlink", Path: name, Err: fs.ErrInvalid}
+	}
+	return string(n.data), nil
+}
+
+func (m *memFS) Open(name string) (io.ReadCloser, error) {
+	if err := m.op("open"); err != nil {
+		return nil, err
+	}
+	n, ok := m.files[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return io.NopCloser(bytes.NewReader(n.data)), nil
+}
+
+func (m *memFS) CreateTemp(dir, pattern string) (File, error) {
+	if err := m.op("create"); err != nil {
+		return nil, err
+	}
+	m.seq++
+	name := filepath.Join(dir, strings.Replace(pattern, "*", fmt.Sprint(m.seq), 1))
+	m.files[name] = &memNode{mode: 0o600}
+	return &memFile{fs: m, name: name}, nil
+}
+
+func (m *memFS) Rename(oldpath, newpath string) error {
+	if err := m.op("rename"); err != nil {
+		return err
+	}
+	n, ok := m.files[oldpath]
+	if !ok {
+		return &fs.PathError{Op: "rename", Path: oldpath, Err: fs.ErrNotExist}
+	}
+	delete(m.files, oldpath)
+	m.files[newpath] = n
+	return nil
+}
+
+func (m *memFS) Remove(name string) error {
+	if err := m.op("remove"); err != nil {
+		return err
+	}
+	if _, ok := m.files[name]; !ok {
+		return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrNotExist}
+	}
+	delete(m.files, name)
+	return nil
+}
+
+func (m *memFS) Link(oldname, newname string) error {
+	if err := m.op("link"); err != nil {
+		return err
+	}
+	m.files[newname] = m.files[oldname]
+	return nil
+}
+
+func (m *memFS) SyncDir(dir string) error { return m.op("syncdir") }
+
+// names 回传所有文件名，排序过
+func (m *memFS) names() []string {
+	var ns []string
+	for n := range m.files {
+		ns = append(ns, n)
+	}
+	slices.Sort(ns)
+	return ns
+}
+
+type memFile struct {
+	fs   *memFS
+	name string
+}
+
+func (f *memFile) Name() string { return f.name }
+
+func (f *memFile) Write(p []byte) (int, error) {
+	if err := f.fs.op("write"); err != nil {
+		return 0, err
+	}
+	n := f.fs.files[f.name]
+	n.data = append(n.data, p...)
+	return len(p), nil
+}
+
+func (f *memFile) Chmod(mode fs.FileMode) error {
+	if err := f.fs.op("chmod"); err != nil {
+		return err
+	}
+	f.fs.files[f.name].mode = mode
+	return nil
+}
+
+func (f *memFile) Sync() error {
+	if err := f.fs.op("sync"); err != nil {
+		return err
+	}
+	f.fs.files[f.name].synced = true
+	return nil
+}
+
+func (f *memFile) Close() error { return f.fs.op("close") }
+
+type memInfo struct {
+	name string
+	node *memNode
+}
+
+func (i memInfo) Name() string       { return i.name }
+func (i memInfo) Size() int64        { return int64(len(i.node.data)) }
+func (i memInfo) Mode() fs.FileMode  { return i.node.mode }
+func (i memInfo) ModTime() time.Time { return time.Time{} }
+func (i memInfo) IsDir() bool        { return false }
+func (i memInfo) Sys() any           { return nil }
+
+func TestWriteFile(t *testing.T) {
+	m := newMemFS()
+	if err := WriteFile("dir/a.txt", []byte("new"), &Options{FS: m}); err != nil {
+		t.Fatal(err)
+	}
+	n := m.files["dir/a.txt"]
+	if string(n.data) != "new" || n.mode != DefaultPerm || !n.synced {
+		t.Error("Expected new 0644 synced Got", string(n.data), n.mode, n.synced)
+	}
+	want := []string{"create", "write", "chmod", "sync", "close", "rename", "syncdir"}
+	if !slices.Equal(m.ops, want) {
+		t.Error("Expected", want, "Got", m.ops)
+	}
+	if names := m.names(); !slices.Equal(names, []string{"dir/a.txt"}) {
+		t.Error("Expected only the target Got", names)
+	}
+}
+
+func TestPreservePerm(t *testing.T) {
+	m := newMemFS()
+	m.files["a.txt"] = &memNode{data: []byte("old"), mode: 0o600}
+	if err := WriteFile("a.txt", []byte("new"), &Options{FS: m, Perm: 0o644}); err != nil {
+		t.Fatal(err)
+	}
+	if n := m.files["a.txt"]; n.mode != 0o600 {
+		t.Error("Expected", fs.FileMode(0o600), "Got", n.mode)
+	}
+
+	if err := WriteFile("b.txt", nil, &Options{FS: m, Perm: 0o640}); err != nil {
+		t.Fatal(err)
+	}
+	if n := m.files["b.txt"]; n.mode != 0o640 {
+		t.Error("Expected", fs.FileMode(0o640), "Got", n.mode)
+	}
+}
+
+func TestBackup(t *testing.T) {
+	m := newMemFS()
+	opts := &Options{FS: m, BackupSuffix: ".bak"}
+
+	// 第一次写入没有旧文件，不会有备份
+	if err := WriteFile("a.txt", []byte("v1"), opts); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := m.files["a.txt.bak"]; ok {
+		t.Error("Expected no backup for a new file")
+	}
+
+	for _, v := range []string{"v2", "v3"} {
+		if err := WriteFile("a.txt", []byte(v), opts); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if x := string(m.files["a.txt"].data); x != "v3" {
+		t.Error("Expected", "v3", "Got", x)
+	}
+	if x := string(m.files["a.txt.bak"].data); x != "v2" {
+		t.Error("Expected", "v2", "Got", x)
+	}
+}
+
+func TestBackupWithoutLinks(t *testing.T) {
+	m := newMemFS()
+	m.files["a.txt"] = &memNode{data: []byte("old"), mode: 0o600}
+	m.fail["link"] = errors.New("link not supported")
+	if err := WriteFile("a.txt", []byte("new"), &Options{FS: m, BackupSuffix: ".bak"}); err != nil {
+		t.Fatal(err)
+	}
+	n := m.files["a.txt.bak"]
+	if n == nil || string(n.data) != "old" || n.mode != 0o600 || !n.synced {
+		t.Error("Expected a synced 0600 copy of old Got", n)
+	}
+	if x := string(m.files["a.txt"].data); x != "new" {
+		t.Error("Expected", "new", "Got", x)
+	}
+	if names := m.names(); !slices.Equal(names, []string{"a.txt", "a.txt.bak"}) {
+		t.Error("Expected a.txt and a.txt.bak Got", names)
+	}
+}
+
+func TestSymlink(t *testing.T) {
+	m := newMemFS()
+	m.files["data/real.txt"] = &memNode{data: []byte("old"), mode: 0o600}
+	m.files["data/link.txt"] = &memNode{data: []byte("real.txt"), mode: fs.ModeSymlink | 0o777}
+	m.files["conf.txt"] = &memNode{data: []byte("data/link.txt"), mode: fs.ModeSymlink | 0o777}
+	m.files["loop"] = &memNode{data: []byte("loop"), mode: fs.ModeSymlink | 0o777}
+
+	// 两层链接，目标被换掉，链接都还在
+	if err := WriteFile("conf.txt", []byte("new"), &Options{FS: m}); err != nil {
+		t.Fatal(err)
+	}
+	if n := m.files["data/real.txt"]; string(n.data) != "new" || n.mode != 0o600 {
+		t.Error("Expected new 0600 Got", string(n.data), n.mode)
+	}
+	if n := m.files["conf.txt"]; n.mode&fs.ModeSymlink == 0 {
+		t.Error("Expected conf.txt to stay a symlink")
+	}
+
+	if _, err := Create("loop", &Options{FS: m}); err == nil {
+		t.Error("Expected an error for a symlink loop")
+	}
+}
+
+// 每一种失败都不能动到目标文件，也不能留下临时文件
+func TestFailures(t *testing.T) {
+	boom := errors.New("boom")
+	type test struct {
+		op     string
+		backup bool
+	}
+	tests := []test{
+		{"create", false},
+		{"write", false},
+		{"chmod", false},
+		{"sync", false},
+		{"close", false},
+		// 不能硬链接时改成复制，复制也失败才算失败
+		{"open", true},
+		{"rename", false},
+	}
+	for _, v := range tests {
+		m := newMemFS()
+		m.files["a.txt"] = &memNode{data: []byte("old"), mode: 0o600}
+		m.fail[v.op] = boom
+		opts := &Options{FS: m}
+		if v.backup {
+			opts.BackupSuffix = ".bak"
+			m.fail["link"] = errors.New("link not supported")
+		}
+
+		err := WriteFile("a.txt", []byte("new"), opts)
+		if !errors.Is(err, boom) {
+			t.Error(v.op, "Expected", boom, "Got", err)
+		}
+		if x := string(m.files["a.txt"].data); x != "old" {
+			t.Error(v.op, "Expected", "old", "Got", x)
+		}
+		if names := m.names(); !slices.Equal(names, []string{"a.txt"}) {
+			t.Error(v.op, "Expected only a.txt Got", names)
+		}
+	}
+}
+
+func TestCombinedErrors(t *testing.T) {
+	writeErr := errors.New("disk full")
+	closeErr := errors.New("close failed")
+	removeErr := errors.New("remove failed")
+
+	m := newMemFS()
+	m.fail["write"] = writeErr
+	m.fail["close"] = closeErr
+	m.fail["remove"] = removeErr
+	err := WriteFile("a.txt", []byte("x"), &Options{FS: m})
+	for _, e := range []error{writeErr, closeErr, removeErr} {
+		if !errors.Is(err, e) {
+			t.Error("Expected", e, "in", err)
+		}
+	}
+	// 写入失败后就不再 chmod、sync、rename
+	for _, op := range []string{"chmod", "sync", "rename"} {
+		if slices.Contains(m.ops, op) {
+			t.Error("Expected no", op, "after a failed write Got", m.ops)
+		}
+	}
+}
+
+// rename 成功之后目录 fsync 失败：回报错误，但目标文件已经是新的
+func TestSyncDirFailure(t *testing.T) {
+	boom := errors.New("boom")
+	m := newMemFS()
+	m.fail["syncdir"] = boom
+	if err := WriteFile("a.txt", []byte("new"), &Options{FS: m}); !errors.Is(err, boom) {
+		t.Error("Expected", boom, "Got", err)
+	}
+	if x := string(m.files["a.txt"].data); x != "new" {
+		t.Error("Expected", "new", "Got", x)
+	}
+}
+
+func TestWriterLifecycle(t *testing.T) {
+	m := newMemFS()
+	m.files["a.txt"] = &memNode{data: []byte("old"), mode: 0o644}
+
+	w, err := Create("a.txt", &Options{FS: m})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fmt.Fprint(w, "half")
+	if err := w.Abort(); err != nil {
+		t.Error("Abort:", err)
+	}
+	if x := string(m.files["a.txt"].data); x != "old" {
+		t.Error("Expected", "old", "Got", x)
+	}
+	if _, err := w.Write([]byte("x")); err != ErrClosed {
+		t.Error("Expected", ErrClosed, "Got", err)
+	}
+	if err := w.Close(); err != ErrClosed {
+		t.Error("Expected", ErrClosed, "Got", err)
+	}
+	if err := w.Abort(); err != nil {
+		t.Error("second Abort:", err)
+	}
+
+	m.files["dir"] = &memNode{mode: fs.ModeDir | 0o755}
+	if _, err := Create("dir", &Options{FS: m}); err == nil {
+		t.Error("Expected an error for a directory")
+	}
+}
+
+// 用真正的文件系统跑一次
+func TestOS(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "example.txt")
+	if err := os.WriteFile(name, []byte("old"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFile(name, []byte("Hello, Go file writing!"), &Options{BackupSuffix: ".bak"}); err != nil {
+		t.Fatal(err)
+	}
+
+	type test struct {
+		name string
+		data string
+	}
+	for _, v := range []test{{name, "Hello, Go file writing!"}, {name + ".bak", "old"}} {
+		b, err := os.ReadFile(v.name)
+		if err != nil || string(b) != v.data {
+			t.Error("Expected", v.data, "Got", string(b), err)
+		}
+	}
+	if fi, err := os.Stat(name); err != nil || fi.Mode().Perm() != 0o600 {
+		t.Error("Expected", fs.FileMode(0o600), "Got", fi, err)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 2 {
+		t.Error("Expected 2 files Got", entries)
+	}
+}
+
+func TestOSSymlink(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "target.txt")
+	link := filepath.Join(dir, "link.txt")
+	if err := os.WriteFile(target, []byte("old"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("target.txt", link); err != nil {
+		t.Skip("symlinks not supported:", err)
+	}
+	if err := WriteFile(link, []byte("new"), &Options{BackupSuffix: ".bak"}); err != nil {
+		t.Fatal(err)
+	}
+	if fi, err := os.Lstat(link); err != nil || fi.Mode()&fs.ModeSymlink == 0 {
+		t.Error("Expected link.txt to stay a symlink Got", fi, err)
+	}
+	for name, want := range map[string]string{target: "new", link: "new", target + ".bak": "old"} {
+		if b, err := os.ReadFile(name); err != nil || string(b) != want {
+			t.Error(name, "Expected", want, "Got", string(b), err)
+		}
+	}
+}
+
+func ExampleWriteFile() {
+	dir, _ := os.MkdirTemp("", "atomicfile")
+	defer os.RemoveAll(dir)
+	name := filepath.Join(dir, "example.txt")
+
+	WriteFile(name, []byte("v1"), nil)
+	err := WriteFile(name, []byte("v2"), &Options{BackupSuffix: ".bak"})
+	fmt.Println(err)
+
+	b, _ := os.ReadFile(name)
+	bak, _ := os.ReadFile(name + ".bak")
+	fmt.Println(string(b), string(bak))
+	// Output:
+	// <nil>
+	// v2 v1
+}
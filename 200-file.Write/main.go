@@ -4,6 +4,8 @@ package main
 import (
     "fmt"
     "os"
+
+    "github.com/andyrestart9/animalPackage/200-file.Write/atomicfile"
 )
 
 func main() {
@@ -13,15 +15,30 @@ func main() {
         fmt.Println("Error creating file:", err)
         return
     }
-    defer file.Close() // 确保在函数结束时关闭文件
 
     // 写入内容到文件
     content := []byte("Hello, Go file writing!")
     _, err = file.Write(content)
+    // 不用 defer file.Close()：Close 也可能失败（很多文件系统到 Close 才回报写入错误），要检查
+    if cerr := file.Close(); err == nil {
+        err = cerr
+    }
     if err != nil {
         fmt.Println("Error writing to file:", err)
         return
     }
 
     fmt.Println("File written successfully!")
+
+    // 上面写到一半崩溃的话，example.txt 只会剩下一半。
+    // atomicfile 先写临时文件、fsync，再 rename 覆盖，读到的不是旧内容就是新内容；
+    // BackupSuffix 会把旧的版本留成 example.txt.bak
+    content = []byte("Hello, atomic file writing!")
+    err = atomicfile.WriteFile("example.txt", content, &atomicfile.Options{BackupSuffix: ".bak"})
+    if err != nil {
+        fmt.Println("Error writing to file:", err)
+        return
+    }
+
+    fmt.Println("File replaced atomically!")
 }
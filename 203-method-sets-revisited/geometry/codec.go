@@ -0,0 +1,257 @@
+package geometry
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// encoding/json 沒辦法直接解碼到介面，因為它不知道要建哪個具體型別。
+// 這裡用一個 "type" 欄位當作判別值 (discriminator)：
+//
+//	{"type":"circle","center":{"x":0,"y":0},"radius":5}
+//
+// 解碼時先讀 "type"，在 registry 找到對應的型別，再把其餘欄位解到那個型別的指標上。
+
+// TypeKey is the JSON field that names the concrete shape type.
+const TypeKey = "type"
+
+var (
+	// ErrUnknownShape is returned for a type name or Go type that is not registered.
+	ErrUnknownShape = errors.New("geometry: unknown shape type")
+	// ErrMissingField is returned when a required field is absent from the JSON.
+	ErrMissingField = errors.New("geometry: missing field")
+)
+
+var registry = struct {
+	sync.RWMutex
+	byName   map[string]reflect.Type   // "circle" -> Circle
+	byType   map[reflect.Type]string   // Circle -> "circle"
+	required map[reflect.Type][]string // Circle -> ["radius"]
+}{
+	byName:   make(map[string]reflect.Type),
+	byType:   make(map[reflect.Type]string),
+	required: make(map[reflect.Type][]string),
+}
+
+func init() {
+	// 圓心和左下角省略時就是原點
+	RegisterShape[Circle]("circle", "center")
+	RegisterShape[Rectangle]("rectangle", "origin")
+	RegisterShape[Triangle]("triangle")
+	RegisterShape[Polygon]("polygon")
+}
+
+// RegisterShape makes *T encodable and decodable under name.
+// Every JSON field of T must be present when decoding, except the ones
+// listed in optional, which keep their zero value when absent.
+// T 和 *T 都會編碼成 name，解碼一律回傳 *T，因為 *T 的方法集一定包含 T 的方法。
+// It panics if name or T is already registered, or if optional names a
+// field T does not have.
+func RegisterShape[T any, PT interface {
+	*T
+	Shape
+}](name string, optional ...string) {
+	t := reflect.TypeFor[T]()
+	fields := jsonFields(t)
+	for _, f := range optional {
+		if !slices.Contains(fields, f) {
+			panic(fmt.Sprintf("geometry: %v has no field %q", t, f))
+		}
+	}
+	required := slices.DeleteFunc(fields, func(f string) bool { return slices.Contains(optional, f) })
+
+	registry.Lock()
+	defer registry.Unlock()
+	if _, ok := registry.byName[name]; ok {
+		panic(fmt.Sprintf("geometry: shape %q registered twice", name))
+	}
+	if _, ok := registry.byType[t]; ok {
+		panic(fmt.Sprintf("geometry: type %v registered twice", t))
+	}
+	registry.byName[name] = t
+	registry.byType[t] = name
+	registry.required[t] = required
+}
+
+// Marshal encodes s as a JSON object with a "type" field naming its
+// registered type, followed by the fields of the concrete type.
+func Marshal(s Shape) ([]byte, error) {
+	if s == nil {
+		return nil, fmt.Errorf("%w: nil", ErrUnknownShape)
+	}
+	t := reflect.TypeOf(s)
+	if t.Kind() == reflect.Pointer {
+		// json.Marshal 會把 nil 指標編成 null，沒辦法加上 "type"
+		if reflect.ValueOf(s).IsNil() {
+			return nil, fmt.Errorf("geometry: cannot marshal nil %v", t)
+		}
+		t = t.Elem()
+	}
+	registry.RLock()
+	name, ok := registry.byType[t]
+	registry.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %v", ErrUnknownShape, t)
+	}
+
+	fields, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) == 0 || fields[0] != '{' {
+		return nil, fmt.Errorf("geometry: %v does not encode as a JSON object: %s", t, fields)
+	}
+	typ, _ := json.Marshal(name)
+
+	// 把 "type" 放在最前面：{"type":"circle", + 原本物件去掉開頭的 {
+	var b bytes.Buffer
+	b.WriteString(`{"` + TypeKey + `":`)
+	b.Write(typ)
+	if rest := fields[1:]; len(rest) > 1 {
+		b.WriteByte(',')
+		b.Write(rest)
+	} else {
+		b.WriteByte('}')
+	}
+	return b.Bytes(), nil
+}
+
+// Unmarshal decodes a JSON object produced by Marshal into a pointer to
+// the registered concrete type, e.g. *Circle for "circle".
+// Unknown fields and missing required fields are errors; a field is
+// optional only if it was listed when the type was registered.
+func Unmarshal(data []byte) (Shape, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("geometry: decoding shape: %w", err)
+	}
+	if raw == nil {
+		return nil, fmt.Errorf("geometry: decoding shape: null")
+	}
+
+	var name string
+	if rawType, ok := raw[TypeKey]; !ok {
+		return nil, fmt.Errorf("%w %q", ErrMissingField, TypeKey)
+	} else if err := json.Unmarshal(rawType, &name); err != nil {
+		return nil, fmt.Errorf("geometry: field %q must be a string: %w", TypeKey, err)
+	}
+	delete(raw, TypeKey)
+
+	registry.RLock()
+	t, ok := registry.byName[name]
+	required := registry.required[t]
+	registry.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownShape, name)
+	}
+
+	for _, f := range required {
+		if _, ok := raw[f]; !ok {
+			return nil, fmt.Errorf("%w %q for %s", ErrMissingField, f, name)
+		}
+	}
+
+	rest, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	v := reflect.New(t)
+	dec := json.NewDecoder(bytes.NewReader(rest))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v.Interface()); err != nil {
+		return nil, fmt.Errorf("geometry: decoding %s: %w", name, err)
+	}
+	return v.Interface().(Shape), nil
+}
+
+// jsonFields 回傳 t 會編碼出來的 JSON 欄位名稱
+// 跟 encoding/json 一樣，沒有 tag 的匿名嵌入 struct 會被攤平，它的欄位算是 t 的欄位
+func jsonFields(t reflect.Type) []string {
+	var fields []string
+	add := func(name string) {
+		// 外層的欄位優先，嵌入的同名欄位不重複
+		if !slices.Contains(fields, name) {
+			fields = append(fields, name)
+		}
+	}
+	var embedded []reflect.Type
+	for i := range t.NumField() {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		name, _, _ := strings.Cut(tag, ",")
+		if tag == "-" {
+			continue
+		}
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			// 未匯出的嵌入 struct 也會攤平，裡面匯出的欄位照樣編碼
+			if ft.Kind() == reflect.Struct {
+				embedded = append(embedded, ft)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		add(name)
+	}
+	for _, et := range embedded {
+		for _, name := range jsonFields(et) {
+			add(name)
+		}
+	}
+	return fields
+}
+
+// Shapes is a slice of shapes that encodes each element with Marshal and
+// decodes each element with Unmarshal, so it can be used directly in
+// structs passed to encoding/json.
+type Shapes []Shape
+
+func (ss Shapes) MarshalJSON() ([]byte, error) {
+	if ss == nil {
+		return []byte("null"), nil
+	}
+	raws := make([]json.RawMessage, len(ss))
+	for i, s := range ss {
+		b, err := Marshal(s)
+		if err != nil {
+			return nil, fmt.Errorf("shape %d: %w", i, err)
+		}
+		raws[i] = b
+	}
+	return json.Marshal(raws)
+}
+
+func (ss *Shapes) UnmarshalJSON(data []byte) error {
+	var raws []json.RawMessage
+	if err := json.Unmarshal(data, &raws); err != nil {
+		return err
+	}
+	if raws == nil {
+		*ss = nil
+		return nil
+	}
+	out := make(Shapes, len(raws))
+	for i, raw := range raws {
+		s, err := Unmarshal(raw)
+		if err != nil {
+			return fmt.Errorf("shape %d: %w", i, err)
+		}
+		out[i] = s
+	}
+	*ss = out
+	return nil
+}
@@ -0,0 +1,204 @@
+package geometry
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// hexagon 沒有註冊，用來測試 Marshal 的錯誤
+type hexagon struct{ Polygon }
+
+func TestRoundTrip(t *testing.T) {
+	tests := []Shape{
+		&Circle{Point{1, 2}, 5},
+		&Circle{Radius: 5},
+		Rectangle{Point{1, 2}, 3, 4},
+		&Rectangle{Width: 3, Height: 4},
+		Triangle{Point{0, 0}, Point{4, 0}, Point{0, 3}},
+		Polygon{[]Point{{0, 0}, {2, 0}, {2, 1}, {1, 1}, {1, 2}, {0, 2}}},
+	}
+
+	for _, v := range tests {
+		data, err := Marshal(v)
+		if err != nil {
+			t.Errorf("Marshal(%#v): %v", v, err)
+			continue
+		}
+		got, err := Unmarshal(data)
+		if err != nil {
+			t.Errorf("Unmarshal(%s): %v", data, err)
+			continue
+		}
+
+		// 解碼一律得到指標，內容要跟原本的值一樣
+		rv := reflect.ValueOf(got)
+		if rv.Kind() != reflect.Pointer {
+			t.Errorf("Unmarshal(%s): Expected a pointer Got %T", data, got)
+			continue
+		}
+		want := reflect.Indirect(reflect.ValueOf(v)).Interface()
+		if !reflect.DeepEqual(rv.Elem().Interface(), want) {
+			t.Errorf("round trip of %s: Expected %#v Got %#v", data, want, rv.Elem().Interface())
+		}
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	type test struct {
+		s      Shape
+		answer string
+	}
+	tests := []test{
+		{&Circle{Radius: 5}, `{"type":"circle","center":{"x":0,"y":0},"radius":5}`},
+		{Rectangle{Width: 3, Height: 4}, `{"type":"rectangle","origin":{"x":0,"y":0},"width":3,"height":4}`},
+		{Polygon{[]Point{{1, 2}}}, `{"type":"polygon","points":[{"x":1,"y":2}]}`},
+	}
+	for _, v := range tests {
+		data, err := Marshal(v.s)
+		if err != nil || string(data) != v.answer {
+			t.Errorf("Marshal(%#v): Expected %s Got %s, %v", v.s, v.answer, data, err)
+		}
+	}
+
+	if _, err := Marshal(hexagon{}); !errors.Is(err, ErrUnknownShape) {
+		t.Error("got", err, "want", ErrUnknownShape)
+	}
+	if _, err := Marshal(nil); !errors.Is(err, ErrUnknownShape) {
+		t.Error("got", err, "want", ErrUnknownShape)
+	}
+	// nil 指標不能編出 {"type":"circle",ull 這種東西
+	if data, err := Marshal((*Circle)(nil)); err == nil {
+		t.Error("Expected an error Got", string(data))
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	type test struct {
+		data string
+		err  error // nil 表示只要有錯誤就好
+	}
+	tests := []test{
+		{`{"type":"hexagon","side":1}`, ErrUnknownShape},
+		{`{"radius":5}`, ErrMissingField},
+		{`{"type":"circle"}`, ErrMissingField},
+		{`{"type":"rectangle","width":3}`, ErrMissingField},
+		{`{"type":"triangle","a":{"x":0,"y":0},"b":{"x":1,"y":0}}`, ErrMissingField},
+		{`{"type":"circle","radius":5,"colour":"red"}`, nil},
+		{`{"type":"circle","radius":"five"}`, nil},
+		{`{"type":5,"radius":5}`, nil},
+		{`[1,2]`, nil},
+		{`null`, nil},
+	}
+
+	// center 註冊成選填，可以省略
+	if _, err := Unmarshal([]byte(`{"type":"circle","radius":5}`)); err != nil {
+		t.Error("unexpected error:", err)
+	}
+
+	for _, v := range tests {
+		_, err := Unmarshal([]byte(v.data))
+		switch {
+		case err == nil:
+			t.Error(v.data, "expected an error")
+		case v.err != nil && !errors.Is(err, v.err):
+			t.Error(v.data, "got", err, "want", v.err)
+		}
+	}
+}
+
+func TestShapesInStruct(t *testing.T) {
+	type drawing struct {
+		Name   string `json:"name"`
+		Shapes Shapes `json:"shapes"`
+	}
+	in := drawing{"house", Shapes{
+		&Rectangle{Width: 4, Height: 3},
+		&Triangle{Point{0, 3}, Point{4, 3}, Point{2, 5}},
+	}}
+
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out drawing
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(in, out) {
+		t.Errorf("Expected %#v Got %#v", in, out)
+	}
+
+	err = json.Unmarshal([]byte(`{"shapes":[{"type":"circle","radius":1},{"type":"blob"}]}`), &out)
+	if !errors.Is(err, ErrUnknownShape) {
+		t.Error("got", err, "want", ErrUnknownShape)
+	}
+}
+
+func TestRegisterShapeTwice(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic")
+		}
+	}()
+	RegisterShape[Circle]("circle")
+}
+
+// labeled 嵌入 Rectangle，JSON 欄位會被攤平成 origin、width、height、label
+type labeled struct {
+	Rectangle
+	Label string `json:"label"`
+}
+
+func TestEmbeddedShape(t *testing.T) {
+	RegisterShape[labeled]("labeled", "origin")
+
+	in := &labeled{Rectangle{Point{1, 2}, 3, 4}, "door"}
+	data, err := Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"type":"labeled","origin":{"x":1,"y":2},"width":3,"height":4,"label":"door"}`
+	if string(data) != want {
+		t.Error("Expected", want, "Got", string(data))
+	}
+	got, err := Unmarshal(data)
+	if err != nil || !reflect.DeepEqual(got, in) {
+		t.Error("Expected", in, "Got", got, err)
+	}
+
+	// 嵌入的欄位一樣是必填的，origin 註冊成選填
+	if _, err := Unmarshal([]byte(`{"type":"labeled","width":3,"height":4,"label":"x"}`)); err != nil {
+		t.Error("unexpected error:", err)
+	}
+	if _, err := Unmarshal([]byte(`{"type":"labeled","width":3,"label":"x"}`)); !errors.Is(err, ErrMissingField) {
+		t.Error("got", err, "want", ErrMissingField)
+	}
+}
+
+func TestRegisterShapeBadOptional(t *testing.T) {
+	type square struct{ Rectangle }
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic")
+		}
+	}()
+	RegisterShape[square]("square", "side")
+}
+
+func ExampleUnmarshal() {
+	s, err := Unmarshal([]byte(`{"type":"circle","radius":5}`))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Printf("%T %.2f\n", s, s.Area())
+
+	_, err = Unmarshal([]byte(`{"type":"circle"}`))
+	fmt.Println(err)
+	// Output:
+	// *geometry.Circle 78.54
+	// geometry: missing field "radius" for circle
+}
@@ -0,0 +1,215 @@
+// Package geometry 把 203-method-sets-revisited 的 shape 介面擴充成一個小型幾何函式庫。
+//
+// 方法集的規則跟 main.go 註解說的一樣：值接收器的方法同時在 T 和 *T 的方法集裡。
+// 這裡的圖形全部用值接收器，所以 Circle{} 和 &Circle{} 都實作 Shape，
+// 用起來不用記哪個圖形要傳指標；指標接收器的例子留在 main.go 的 circle。
+package geometry
+
+import (
+	"cmp"
+	"math"
+	"slices"
+)
+
+// Point is a point in the plane.
+type Point struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// Box is an axis-aligned rectangle given by its min and max corners.
+type Box struct {
+	Min Point `json:"min"`
+	Max Point `json:"max"`
+}
+
+// Contains reports whether p is inside b or on its edge.
+func (b Box) Contains(p Point) bool {
+	return p.X >= b.Min.X && p.X <= b.Max.X && p.Y >= b.Min.Y && p.Y <= b.Max.Y
+}
+
+// Shape is a closed figure in the plane.
+type Shape interface {
+	Area() float64
+	Perimeter() float64
+	BoundingBox() Box
+	Contains(p Point) bool
+}
+
+// --------- Circle：值接收器 ---------
+
+// Circle is a circle.
+type Circle struct {
+	Center Point   `json:"center"`
+	Radius float64 `json:"radius"`
+}
+
+func (c Circle) Area() float64 { return math.Pi * c.Radius * c.Radius }
+
+func (c Circle) Perimeter() float64 { return 2 * math.Pi * c.Radius }
+
+func (c Circle) BoundingBox() Box {
+	return Box{
+		Min: Point{c.Center.X - c.Radius, c.Center.Y - c.Radius},
+		Max: Point{c.Center.X + c.Radius, c.Center.Y + c.Radius},
+	}
+}
+
+func (c Circle) Contains(p Point) bool {
+	return math.Hypot(p.X-c.Center.X, p.Y-c.Center.Y) <= c.Radius
+}
+
+// --------- Rectangle：值接收器 ---------
+
+// Rectangle is an axis-aligned rectangle with its lower-left corner at Origin.
+type Rectangle struct {
+	Origin Point   `json:"origin"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+func (r Rectangle) Area() float64 { return r.Width * r.Height }
+
+func (r Rectangle) Perimeter() float64 { return 2 * (r.Width + r.Height) }
+
+func (r Rectangle) BoundingBox() Box {
+	return Box{Min: r.Origin, Max: Point{r.Origin.X + r.Width, r.Origin.Y + r.Height}}
+}
+
+func (r Rectangle) Contains(p Point) bool { return r.BoundingBox().Contains(p) }
+
+// --------- Triangle：值接收器 ---------
+
+// Triangle is a triangle given by its three vertices.
+type Triangle struct {
+	A Point `json:"a"`
+	B Point `json:"b"`
+	C Point `json:"c"`
+}
+
+func (t Triangle) Area() float64 { return math.Abs(cross(t.A, t.B, t.C)) / 2 }
+
+func (t Triangle) Perimeter() float64 { return dist(t.A, t.B) + dist(t.B, t.C) + dist(t.C, t.A) }
+
+func (t Triangle) BoundingBox() Box { return boundingBox([]Point{t.A, t.B, t.C}) }
+
+// Contains reports whether p is inside t or on its edge. A degenerate
+// triangle, whose vertices are collinear, has no interior and contains
+// only the points on its edges.
+func (t Triangle) Contains(p Point) bool {
+	// 三點共線時下面的判斷對線上的每個點都成立，連線段外面的也算進去
+	if cross(t.A, t.B, t.C) == 0 {
+		return onSegment(t.A, t.B, p) || onSegment(t.B, t.C, p) || onSegment(t.C, t.A, p)
+	}
+	// p 在三條邊的同一側（或邊上）就在三角形裡
+	d1, d2, d3 := cross(t.A, t.B, p), cross(t.B, t.C, p), cross(t.C, t.A, p)
+	hasNeg := d1 < 0 || d2 < 0 || d3 < 0
+	hasPos := d1 > 0 || d2 > 0 || d3 > 0
+	return !(hasNeg && hasPos)
+}
+
+// --------- Polygon：值接收器 ---------
+
+// Polygon is a simple polygon given by its vertices in order.
+// 不需要重複第一個點來閉合。
+type Polygon struct {
+	Points []Point `json:"points"`
+}
+
+// Area uses the shoelace formula.
+func (pg Polygon) Area() float64 {
+	var s float64
+	for i, p := range pg.Points {
+		q := pg.Points[(i+1)%len(pg.Points)]
+		s += p.X*q.Y - q.X*p.Y
+	}
+	return math.Abs(s) / 2
+}
+
+func (pg Polygon) Perimeter() float64 {
+	var s float64
+	for i, p := range pg.Points {
+		s += dist(p, pg.Points[(i+1)%len(pg.Points)])
+	}
+	return s
+}
+
+func (pg Polygon) BoundingBox() Box { return boundingBox(pg.Points) }
+
+// Contains uses ray casting: a point is inside if a ray from it crosses
+// the edges an odd number of times. Points on an edge count as inside.
+func (pg Polygon) Contains(p Point) bool {
+	inside := false
+	n := len(pg.Points)
+	for i, j := 0, n-1; i < n; j, i = i, i+1 {
+		a, b := pg.Points[i], pg.Points[j]
+		if onSegment(a, b, p) {
+			return true
+		}
+		if (a.Y > p.Y) != (b.Y > p.Y) && p.X < (b.X-a.X)*(p.Y-a.Y)/(b.Y-a.Y)+a.X {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// --------- 集合的工具函式 ---------
+
+// SortByArea sorts shapes by ascending area, keeping the original order
+// of shapes with equal area.
+func SortByArea(shapes []Shape) {
+	slices.SortStableFunc(shapes, func(a, b Shape) int {
+		return cmp.Compare(a.Area(), b.Area())
+	})
+}
+
+// TotalArea returns the sum of the areas of shapes.
+func TotalArea(shapes []Shape) float64 {
+	var total float64
+	for _, s := range shapes {
+		total += s.Area()
+	}
+	return total
+}
+
+// Largest returns the shape with the largest area, or false if shapes is
+// empty. Ties go to the first one.
+func Largest(shapes []Shape) (Shape, bool) {
+	if len(shapes) == 0 {
+		return nil, false
+	}
+	largest := shapes[0]
+	for _, s := range shapes[1:] {
+		if s.Area() > largest.Area() {
+			largest = s
+		}
+	}
+	return largest, true
+}
+
+// --------- 內部用的向量運算 ---------
+
+// cross 回傳 (b-a) x (c-a)，正數表示 c 在 ab 的左邊
+func cross(a, b, c Point) float64 {
+	return (b.X-a.X)*(c.Y-a.Y) - (b.Y-a.Y)*(c.X-a.X)
+}
+
+func dist(a, b Point) float64 { return math.Hypot(b.X-a.X, b.Y-a.Y) }
+
+func onSegment(a, b, p Point) bool {
+	return cross(a, b, p) == 0 &&
+		p.X >= min(a.X, b.X) && p.X <= max(a.X, b.X) &&
+		p.Y >= min(a.Y, b.Y) && p.Y <= max(a.Y, b.Y)
+}
+
+func boundingBox(ps []Point) Box {
+	if len(ps) == 0 {
+		return Box{}
+	}
+	b := Box{Min: ps[0], Max: ps[0]}
+	for _, p := range ps[1:] {
+		b.Min.X, b.Min.Y = min(b.Min.X, p.X), min(b.Min.Y, p.Y)
+		b.Max.X, b.Max.Y = max(b.Max.X, p.X), max(b.Max.Y, p.Y)
+	}
+	return b
+}
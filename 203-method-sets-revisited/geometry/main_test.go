@@ -0,0 +1,172 @@
+package geometry
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// 編譯期檢查：這幾行編譯得過，就代表這些型別實作了 Shape
+var (
+	_ Shape = Circle{}
+	_ Shape = (*Circle)(nil)
+	_ Shape = Rectangle{}
+	_ Shape = (*Rectangle)(nil)
+	_ Shape = Triangle{}
+	_ Shape = Polygon{}
+)
+
+// TestMethodSets 用 reflect 檢查方法集：
+// 指標接收器的方法只在 *T 的方法集裡；值接收器的方法同時在 T 和 *T 的方法集裡
+func TestMethodSets(t *testing.T) {
+	shape := reflect.TypeFor[Shape]()
+
+	type test struct {
+		typ        reflect.Type
+		implements bool
+	}
+	tests := []test{
+		{reflect.TypeFor[Circle](), true},
+		{reflect.TypeFor[*Circle](), true},
+		{reflect.TypeFor[Rectangle](), true},
+		{reflect.TypeFor[*Rectangle](), true},
+		{reflect.TypeFor[Triangle](), true},
+		{reflect.TypeFor[*Triangle](), true},
+		{reflect.TypeFor[Polygon](), true},
+		{reflect.TypeFor[*Polygon](), true},
+	}
+	for _, v := range tests {
+		if v.typ.Implements(shape) != v.implements {
+			t.Error(v.typ, "implements Shape: Expected", v.implements, "Got", !v.implements)
+		}
+	}
+
+	// 值接收器的四個方法 Circle 和 *Circle 都有
+	if n := reflect.TypeFor[Circle]().NumMethod(); n != 4 {
+		t.Error("Circle method set: Expected", 4, "Got", n)
+	}
+	if n := reflect.TypeFor[*Circle]().NumMethod(); n != 4 {
+		t.Error("*Circle method set: Expected", 4, "Got", n)
+	}
+}
+
+// TestAddressable 是 main.go 第一則註解反過來的情況：Area 是值接收器，
+// (&c).Area() 會被自動轉成 (*&c).Area()
+func TestAddressable(t *testing.T) {
+	c := Circle{Radius: 1}
+	if c.Area() != (&c).Area() {
+		t.Error("c.Area() and (&c).Area() differ")
+	}
+}
+
+func TestShapes(t *testing.T) {
+	type test struct {
+		name      string
+		s         Shape
+		area      float64
+		perimeter float64
+		box       Box
+	}
+	tests := []test{
+		{"circle", Circle{Point{1, 1}, 2}, 4 * math.Pi, 4 * math.Pi, Box{Point{-1, -1}, Point{3, 3}}},
+		{"rectangle", Rectangle{Point{1, 2}, 3, 4}, 12, 14, Box{Point{1, 2}, Point{4, 6}}},
+		{"triangle", Triangle{Point{0, 0}, Point{4, 0}, Point{0, 3}}, 6, 12, Box{Point{0, 0}, Point{4, 3}}},
+		{"square polygon", Polygon{[]Point{{0, 0}, {2, 0}, {2, 2}, {0, 2}}}, 4, 8, Box{Point{0, 0}, Point{2, 2}}},
+		{"L polygon", Polygon{[]Point{{0, 0}, {2, 0}, {2, 1}, {1, 1}, {1, 2}, {0, 2}}}, 3, 8, Box{Point{0, 0}, Point{2, 2}}},
+	}
+	for _, v := range tests {
+		if x := v.s.Area(); !near(x, v.area) {
+			t.Error(v.name, "area: Expected", v.area, "Got", x)
+		}
+		if x := v.s.Perimeter(); !near(x, v.perimeter) {
+			t.Error(v.name, "perimeter: Expected", v.perimeter, "Got", x)
+		}
+		if x := v.s.BoundingBox(); x != v.box {
+			t.Error(v.name, "bounding box: Expected", v.box, "Got", x)
+		}
+	}
+}
+
+func TestContains(t *testing.T) {
+	lShape := Polygon{[]Point{{0, 0}, {2, 0}, {2, 1}, {1, 1}, {1, 2}, {0, 2}}}
+
+	type test struct {
+		name   string
+		s      Shape
+		p      Point
+		answer bool
+	}
+	tests := []test{
+		{"circle center", Circle{Point{1, 1}, 2}, Point{1, 1}, true},
+		{"circle edge", &Circle{Point{1, 1}, 2}, Point{3, 1}, true},
+		{"circle box corner", Circle{Point{1, 1}, 2}, Point{2.9, 2.9}, false},
+		{"rectangle inside", Rectangle{Point{0, 0}, 2, 1}, Point{1, 0.5}, true},
+		{"rectangle outside", Rectangle{Point{0, 0}, 2, 1}, Point{1, 1.5}, false},
+		{"triangle inside", Triangle{Point{0, 0}, Point{4, 0}, Point{0, 3}}, Point{1, 1}, true},
+		{"triangle edge", Triangle{Point{0, 0}, Point{4, 0}, Point{0, 3}}, Point{2, 0}, true},
+		{"triangle outside", Triangle{Point{0, 0}, Point{4, 0}, Point{0, 3}}, Point{3, 2}, false},
+		{"flat triangle on segment", Triangle{Point{0, 0}, Point{4, 0}, Point{2, 0}}, Point{3, 0}, true},
+		{"flat triangle past segment", Triangle{Point{0, 0}, Point{4, 0}, Point{2, 0}}, Point{5, 0}, false},
+		{"flat triangle off line", Triangle{Point{0, 0}, Point{4, 0}, Point{2, 0}}, Point{1, 1}, false},
+		{"point triangle at vertex", Triangle{Point{1, 1}, Point{1, 1}, Point{1, 1}}, Point{1, 1}, true},
+		{"point triangle elsewhere", Triangle{Point{1, 1}, Point{1, 1}, Point{1, 1}}, Point{2, 2}, false},
+		{"polygon inside", lShape, Point{0.5, 1.5}, true},
+		{"polygon notch", lShape, Point{1.5, 1.5}, false},
+		{"polygon vertex", lShape, Point{1, 1}, true},
+		{"polygon outside", lShape, Point{3, 0}, false},
+	}
+	for _, v := range tests {
+		if x := v.s.Contains(v.p); x != v.answer {
+			t.Error(v.name, "Expected", v.answer, "Got", x)
+		}
+	}
+}
+
+func TestCollection(t *testing.T) {
+	small := Rectangle{Width: 1, Height: 1}
+	tri := Triangle{Point{0, 0}, Point{4, 0}, Point{0, 3}}
+	big := &Circle{Radius: 3}
+	same := Rectangle{Width: 6, Height: 1}
+
+	shapes := []Shape{big, tri, small, same}
+	SortByArea(shapes)
+	want := []Shape{small, tri, same, big}
+	if !reflect.DeepEqual(shapes, want) {
+		t.Error("Expected", want, "Got", shapes)
+	}
+
+	if x := TotalArea(shapes); !near(x, 1+6+6+9*math.Pi) {
+		t.Error("Expected", 1+6+6+9*math.Pi, "Got", x)
+	}
+
+	if s, ok := Largest(shapes); !ok || s != Shape(big) {
+		t.Error("Expected", big, "Got", s, ok)
+	}
+	// 面積一樣時回傳第一個
+	if s, _ := Largest([]Shape{tri, same}); s != Shape(tri) {
+		t.Error("Expected", tri, "Got", s)
+	}
+	if s, ok := Largest(nil); ok || s != nil {
+		t.Error("Expected nil, false Got", s, ok)
+	}
+	if x := TotalArea(nil); x != 0 {
+		t.Error("Expected", 0, "Got", x)
+	}
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func ExampleLargest() {
+	shapes := []Shape{
+		&Circle{Radius: 1},
+		Rectangle{Width: 2, Height: 3},
+		Triangle{Point{0, 0}, Point{4, 0}, Point{0, 4}},
+	}
+	s, _ := Largest(shapes)
+	fmt.Printf("%T %.1f\n", s, s.Area())
+	// Output:
+	// geometry.Triangle 8.0
+}
@@ -3,6 +3,8 @@ package main
 import (
 	"fmt"
 	"math"
+
+	"github.com/andyrestart9/animalPackage/203-method-sets-revisited/geometry"
 )
 
 type circle struct {
@@ -34,6 +36,18 @@ func main() {
 	// 不可尋址的（Non-addressable）的表達式：常量與字面值、臨時值、函數返回值、map 的元素、直接從通道接收的值
 	// 詳細可以看下面第一個註解
 	fmt.Println(c.area())
+
+	// geometry 套件的圖形全部用值接收器，值和指標都可以放進 []geometry.Shape
+	shapes := []geometry.Shape{
+		geometry.Circle{Radius: 5}, // 不像上面的 circle，這裡不用取址
+		geometry.Rectangle{Width: 4, Height: 3},
+		geometry.Triangle{A: geometry.Point{X: 0, Y: 0}, B: geometry.Point{X: 4, Y: 0}, C: geometry.Point{X: 0, Y: 3}},
+	}
+	geometry.SortByArea(shapes)
+	for _, s := range shapes {
+		fmt.Printf("%T area: %.2f perimeter: %.2f\n", s, s.Area(), s.Perimeter())
+	}
+	fmt.Printf("total area: %.2f\n", geometry.TotalArea(shapes))
 }
 
 /*
@@ -0,0 +1,313 @@
+// Package interleave replays a critical section under every (or a seeded
+// random sample of) interleaving of virtual goroutines, and reports the
+// schedules that produce a wrong result.
+//
+// 205-race-condition 用 runtime.Gosched() 讓 lost update 比較容易出現，但結果還是看排程器的心情，
+// 只能說「多跑幾次就會看到」。這裡不開真的 goroutine：每個虛擬 goroutine 是一串 Step，
+// Schedule 決定每一步輪到誰，同一個 Schedule 每次跑出來的結果都一樣，可以直接貼到教材裡。
+//
+//	p := interleave.Counter(2)
+//	r, _ := interleave.Explore(p)
+//	fmt.Println(r.Failures[0])
+//
+// Step 之間就是可能被打斷的地方；一個 Step 裡面的動作視為不可分割。
+package interleave
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
+)
+
+// ErrTooMany is returned by Explore when the program has more schedules
+// than MaxSchedules.
+var ErrTooMany = errors.New("interleave: too many schedules to explore, use Sample")
+
+// MaxSchedules bounds Explore. 三個 goroutine 各四步就有 34650 種排法，成長得非常快。
+var MaxSchedules = 1_000_000
+
+// Step is one indivisible action of a virtual goroutine. S is the shared
+// state and L is the goroutine's local variables.
+type Step[S, L any] struct {
+	Name string // 印在 trace 裡，例如 "v := counter"
+	Run  func(shared *S, local *L)
+}
+
+// Program is a critical section run by Goroutines virtual goroutines.
+type Program[S, L any] struct {
+	Goroutines int
+	Steps      []Step[S, L]
+	// Init 回傳初始的共用狀態，nil 表示零值
+	Init func() S
+	// Check 回傳 nil 表示最後的狀態是對的
+	Check func(S) error
+	// Show 把狀態印成一行放在 trace 裡，nil 表示用 %+v
+	Show func(S, []L) string
+}
+
+// Schedule lists which goroutine runs each step, in order. Goroutine i
+// appears exactly len(Steps) times.
+type Schedule []int
+
+func (s Schedule) String() string {
+	parts := make([]string, len(s))
+	for i, g := range s {
+		parts[i] = fmt.Sprint(g)
+	}
+	return strings.Join(parts, " ")
+}
+
+// Event is one executed step.
+type Event struct {
+	Goroutine int
+	Step      string
+	State     string // 這一步做完之後的狀態
+}
+
+// Run is the outcome of one schedule.
+type Run[S any] struct {
+	Schedule Schedule
+	Trace    []Event
+	Final    S
+	Err      error // Check 的結果
+}
+
+// String renders the run as a timeline, one column per goroutine.
+func (r Run[S]) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "schedule %v: ", r.Schedule)
+	if r.Err != nil {
+		fmt.Fprintf(&b, "%v\n", r.Err)
+	} else {
+		b.WriteString("ok\n")
+	}
+	for _, e := range r.Trace {
+		fmt.Fprintf(&b, "  %sg%d: %-20s %s\n", strings.Repeat("    ", e.Goroutine), e.Goroutine, e.Step, e.State)
+	}
+	return b.String()
+}
+
+// Report summarises a set of runs.
+type Report[S any] struct {
+	Runs     int
+	Failures []Run[S]
+}
+
+// Replay runs p under schedule s.
+func Replay[S, L any](p Program[S, L], s Schedule) (Run[S], error) {
+	if err := p.valid(s); err != nil {
+		return Run[S]{}, err
+	}
+	var shared S
+	if p.Init != nil {
+		shared = p.Init()
+	}
+	locals := make([]L, p.Goroutines)
+	pc := make([]int, p.Goroutines)
+	r := Run[S]{Schedule: slices.Clone(s)}
+	for _, g := range s {
+		step := p.Steps[pc[g]]
+		step.Run(&shared, &locals[g])
+		pc[g]++
+		r.Trace = append(r.Trace, Event{Goroutine: g, Step: step.Name, State: p.show(shared, locals)})
+	}
+	r.Final = shared
+	if p.Check != nil {
+		r.Err = p.Check(shared)
+	}
+	return r, nil
+}
+
+// check 檢查跟排程無關的設定，負數的 Goroutines 會讓 make 直接 panic
+func (p Program[S, L]) check() error {
+	if p.Goroutines < 0 {
+		return fmt.Errorf("interleave: negative goroutine count %d", p.Goroutines)
+	}
+	return nil
+}
+
+func (p Program[S, L]) valid(s Schedule) error {
+	if err := p.check(); err != nil {
+		return err
+	}
+	count := make([]int, p.Goroutines)
+	for _, g := range s {
+		if g < 0 || g >= p.Goroutines {
+			return fmt.Errorf("interleave: goroutine %d out of range in schedule %v", g, s)
+		}
+		count[g]++
+	}
+	for g, n := range count {
+		if n != len(p.Steps) {
+			return fmt.Errorf("interleave: goroutine %d runs %d steps in schedule %v, want %d", g, n, s, len(p.Steps))
+		}
+	}
+	return nil
+}
+
+func (p Program[S, L]) show(s S, locals []L) string {
+	if p.Show != nil {
+		return p.Show(s, locals)
+	}
+	return fmt.Sprintf("%+v %+v", s, locals)
+}
+
+// Count returns the number of distinct schedules of p:
+// (G*n)! / (n!)^G for G goroutines of n steps.
+func (p Program[S, L]) Count() int {
+	total, left := 1, 0
+	// 一個一個 goroutine 加進去：C(已經排好的步數+n, n)
+	for range p.Goroutines {
+		for k := 1; k <= len(p.Steps); k++ {
+			left++
+			total = total * left / k
+			if total > MaxSchedules*10 || total < 0 {
+				return -1 // 太大了，呼叫端只需要知道超過上限
+			}
+		}
+	}
+	return total
+}
+
+// Explore runs p under every schedule, in lexicographic order.
+func Explore[S, L any](p Program[S, L]) (Report[S], error) {
+	if err := p.check(); err != nil {
+		return Report[S]{}, err
+	}
+	if n := p.Count(); n < 0 || n > MaxSchedules {
+		return Report[S]{}, ErrTooMany
+	}
+	var rep Report[S]
+	var err error
+	remaining := make([]int, p.Goroutines)
+	for i := range remaining {
+		remaining[i] = len(p.Steps)
+	}
+	s := make(Schedule, 0, p.Goroutines*len(p.Steps))
+
+	var walk func()
+	walk = func() {
+		if err != nil {
+			return
+		}
+		if len(s) == cap(s) {
+			var r Run[S]
+			if r, err = Replay(p, s); err == nil {
+				rep.add(r)
+			}
+			return
+		}
+		for g := range remaining {
+			if remaining[g] == 0 {
+				continue
+			}
+			remaining[g]--
+			s = append(s, g)
+			walk()
+			s = s[:len(s)-1]
+			remaining[g]++
+		}
+	}
+	walk()
+	return rep, err
+}
+
+// Sample runs p under n random schedules drawn from seed. The same seed
+// always produces the same schedules. Failures are reported once per
+// distinct schedule.
+func Sample[S, L any](p Program[S, L], seed uint64, n int) (Report[S], error) {
+	if err := p.check(); err != nil {
+		return Report[S]{}, err
+	}
+	rng := rand.New(rand.NewPCG(seed, seed^0x9e3779b97f4a7c15))
+	var rep Report[S]
+	seen := map[string]bool{}
+	for range n {
+		s := randomSchedule(rng, p.Goroutines, len(p.Steps))
+		r, err := Replay(p, s)
+		if err != nil {
+			return rep, err
+		}
+		rep.Runs++
+		if r.Err != nil && !seen[s.String()] {
+			seen[s.String()] = true
+			rep.Failures = append(rep.Failures, r)
+		}
+	}
+	return rep, nil
+}
+
+// randomSchedule 把每個 goroutine 的 n 步混在一起洗牌，每種排法出現的機率都一樣
+func randomSchedule(rng *rand.Rand, goroutines, steps int) Schedule {
+	s := make(Schedule, 0, goroutines*steps)
+	for g := range goroutines {
+		for range steps {
+			s = append(s, g)
+		}
+	}
+	rng.Shuffle(len(s), func(i, j int) { s[i], s[j] = s[j], s[i] })
+	return s
+}
+
+func (r *Report[S]) add(run Run[S]) {
+	r.Runs++
+	if run.Err != nil {
+		r.Failures = append(r.Failures, run)
+	}
+}
+
+// CounterState is the shared state of the 205 counter.
+type CounterState struct {
+	Counter int
+}
+
+// CounterLocal is the local variable v of each goroutine in 205.
+type CounterLocal struct {
+	V int
+}
+
+// Counter returns the goroutine body of 205-race-condition:
+//
+//	v := counter
+//	runtime.Gosched()
+//	v++
+//	counter = v
+//
+// run by n goroutines. Check fails unless counter == n.
+// runtime.Gosched() 本身不做事，只是讓出 CPU，在這裡就是 Step 之間的切換點。
+func Counter(n int) Program[CounterState, CounterLocal] {
+	return Program[CounterState, CounterLocal]{
+		Goroutines: n,
+		Steps: []Step[CounterState, CounterLocal]{
+			{"v := counter", func(s *CounterState, l *CounterLocal) { l.V = s.Counter }},
+			{"v++", func(s *CounterState, l *CounterLocal) { l.V++ }},
+			{"counter = v", func(s *CounterState, l *CounterLocal) { s.Counter = l.V }},
+		},
+		Check: func(s CounterState) error {
+			if s.Counter != n {
+				return fmt.Errorf("lost update: counter = %d, want %d", s.Counter, n)
+			}
+			return nil
+		},
+		Show: func(s CounterState, ls []CounterLocal) string {
+			vs := make([]string, len(ls))
+			for i, l := range ls {
+				vs[i] = fmt.Sprintf("v%d=%d", i, l.V)
+			}
+			return fmt.Sprintf("counter=%d %s", s.Counter, strings.Join(vs, " "))
+		},
+	}
+}
+
+// LockedCounter is Counter with the read-modify-write done in a single
+// step, as sync.Mutex in 206 or atomic.AddInt64 in 207 make it. Every
+// schedule is correct.
+func LockedCounter(n int) Program[CounterState, CounterLocal] {
+	p := Counter(n)
+	p.Steps = []Step[CounterState, CounterLocal]{
+		{"lock; counter++; unlock", func(s *CounterState, l *CounterLocal) { s.Counter++; l.V = s.Counter }},
+	}
+	return p
+}
@@ -0,0 +1,179 @@
+package interleave
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCount(t *testing.T) {
+	type test struct {
+		goroutines, steps, answer int
+	}
+	tests := []test{
+		{1, 3, 1},
+		{2, 1, 2},
+		{2, 3, 20},
+		{3, 1, 6},
+		{3, 3, 1680},
+		{3, 4, 34650},
+	}
+	for _, v := range tests {
+		p := Program[int, int]{Goroutines: v.goroutines, Steps: make([]Step[int, int], v.steps)}
+		if x := p.Count(); x != v.answer {
+			t.Error("Data", v.goroutines, v.steps, "Expected", v.answer, "Got", x)
+		}
+	}
+}
+
+// 兩個 goroutine 的 20 種排法裡，只有一個做完另一個才開始的 2 種是對的
+func TestExploreCounter(t *testing.T) {
+	r, err := Explore(Counter(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Runs != 20 || len(r.Failures) != 18 {
+		t.Error("Expected 20 runs and 18 failures Got", r.Runs, len(r.Failures))
+	}
+	for _, f := range r.Failures {
+		if f.Final.Counter != 1 {
+			t.Error("Expected every lost update to end at 1 Got", f.Final.Counter, f.Schedule)
+		}
+	}
+	// 排法依字典順序，第一個失敗的是 0 0 1 0 1 1
+	if x := r.Failures[0].Schedule.String(); x != "0 0 1 0 1 1" {
+		t.Error("Expected", "0 0 1 0 1 1", "Got", x)
+	}
+
+	r, err = Explore(Counter(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 三個 goroutine 的時候最慘可以只剩 1
+	lowest := 3
+	for _, f := range r.Failures {
+		lowest = min(lowest, f.Final.Counter)
+	}
+	if r.Runs != 1680 || lowest != 1 || len(r.Failures) != 1680-6 {
+		t.Error("Expected 1680 runs, 1674 failures, lowest 1 Got", r.Runs, len(r.Failures), lowest)
+	}
+}
+
+func TestLockedCounter(t *testing.T) {
+	r, err := Explore(LockedCounter(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Runs != 24 || len(r.Failures) != 0 {
+		t.Error("Expected 24 runs and no failures Got", r.Runs, r.Failures)
+	}
+}
+
+func TestReplay(t *testing.T) {
+	run, err := Replay(Counter(2), Schedule{0, 1, 0, 1, 0, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.Final.Counter != 1 || run.Err == nil {
+		t.Error("Expected a lost update Got", run.Final, run.Err)
+	}
+	want := []string{
+		"counter=0 v0=0 v1=0",
+		"counter=0 v0=0 v1=0",
+		"counter=0 v0=1 v1=0",
+		"counter=0 v0=1 v1=1",
+		"counter=1 v0=1 v1=1",
+		"counter=1 v0=1 v1=1",
+	}
+	var got []string
+	for _, e := range run.Trace {
+		got = append(got, e.State)
+	}
+	if !slices.Equal(got, want) {
+		t.Error("Expected", want, "Got", got)
+	}
+
+	for _, s := range []Schedule{{0, 0, 0}, {0, 0, 0, 1, 1, 2}, {0, 0, 0, 0, 1, 1}} {
+		if _, err := Replay(Counter(2), s); err == nil {
+			t.Error("Expected an error for schedule", s)
+		}
+	}
+}
+
+func TestSample(t *testing.T) {
+	a, err := Sample(Counter(3), 42, 200)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := Sample(Counter(3), 42, 200)
+	if a.Runs != 200 || len(a.Failures) == 0 {
+		t.Error("Expected 200 runs with failures Got", a.Runs, len(a.Failures))
+	}
+	// 同一個 seed 一定得到一樣的結果
+	if len(a.Failures) != len(b.Failures) || a.Failures[0].Schedule.String() != b.Failures[0].Schedule.String() {
+		t.Error("Expected the same failures for the same seed")
+	}
+	c, _ := Sample(Counter(3), 43, 200)
+	if c.Failures[0].Schedule.String() == a.Failures[0].Schedule.String() && c.Failures[1].Schedule.String() == a.Failures[1].Schedule.String() {
+		t.Error("Expected a different sample for a different seed")
+	}
+	// 每個失敗的排法只回報一次
+	seen := map[string]bool{}
+	for _, f := range a.Failures {
+		if seen[f.Schedule.String()] {
+			t.Error("duplicate failure", f.Schedule)
+		}
+		seen[f.Schedule.String()] = true
+	}
+}
+
+func TestNegativeGoroutines(t *testing.T) {
+	p := Counter(2)
+	p.Goroutines = -1
+	if _, err := Explore(p); err == nil {
+		t.Error("Explore: Expected an error for", p.Goroutines, "goroutines")
+	}
+	if _, err := Replay(p, Schedule{}); err == nil {
+		t.Error("Replay: Expected an error for", p.Goroutines, "goroutines")
+	}
+	if _, err := Sample(p, 42, 1); err == nil {
+		t.Error("Sample: Expected an error for", p.Goroutines, "goroutines")
+	}
+}
+
+func TestTooMany(t *testing.T) {
+	if _, err := Explore(Counter(8)); !errors.Is(err, ErrTooMany) {
+		t.Error("Expected", ErrTooMany, "Got", err)
+	}
+}
+
+func TestRunString(t *testing.T) {
+	run, _ := Replay(Counter(2), Schedule{0, 1, 0, 1, 0, 1})
+	s := run.String()
+	for _, want := range []string{
+		"schedule 0 1 0 1 0 1: lost update: counter = 1, want 2",
+		"  g0: v := counter",
+		"      g1: v := counter",
+	} {
+		if !strings.Contains(s, want) {
+			t.Errorf("output does not contain %q:\n%s", want, s)
+		}
+	}
+}
+
+func ExampleExplore() {
+	r, _ := Explore(Counter(2))
+	fmt.Println(len(r.Failures), "of", r.Runs, "schedules lose an update")
+	fmt.Print(r.Failures[0])
+	// Output:
+	// 18 of 20 schedules lose an update
+	// schedule 0 0 1 0 1 1: lost update: counter = 1, want 2
+	//   g0: v := counter         counter=0 v0=0 v1=0
+	//   g0: v++                  counter=0 v0=1 v1=0
+	//       g1: v := counter         counter=0 v0=1 v1=0
+	//   g0: counter = v          counter=1 v0=1 v1=0
+	//       g1: v++                  counter=1 v0=1 v1=1
+	//       g1: counter = v          counter=1 v0=1 v1=1
+}
@@ -41,6 +41,8 @@ func main() {
 	wg.Wait()
 	fmt.Println("Goroutines:", runtime.NumGoroutine())
 	fmt.Println("count:", counter)
+	// 結果每次跑都可能不一樣。interleave 套件用虛擬 goroutine 列出每一種會丟失更新的執行順序：
+	// go test ./205-race-condition/interleave -run ExampleExplore -v
 	// 用 go run -race main.go 指令，這個指令會告訴你妳的程式有沒有 race condition，最後會出現類似 Found 2 data race(s) 的資訊
 	/*
 		怎麼找到 -race 這個 flag?
@@ -0,0 +1,175 @@
+// Package counter provides concurrency-safe counters.
+//
+// 205、206、207 各自手寫了一個讓 100 個 goroutine 一起加的 counter：
+// 205 沒有保護（race condition），206 用 sync.Mutex，207 用 atomic。
+// 這裡把三種做法整理成同一個介面：
+//
+//	Mutex    每次 Add 都鎖，最好懂
+//	Atomic   用 atomic.Int64，沒有鎖，大部分情況的首選
+//	Sharded  每個 P 一份，Add 幾乎不會互相搶，Load 要把每一份加起來；寫很多、讀很少時用
+//
+// 用 go test -bench . -cpu 1,2,4,8 比較三種在不同 GOMAXPROCS 下的差別。
+package counter
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// Counter is a shared int64 that many goroutines can update.
+type Counter interface {
+	Add(delta int64)
+	// Load returns the current value.
+	Load() int64
+	// Reset sets the value to zero.
+	Reset()
+	// Snapshot returns the value together with its internal layout.
+	Snapshot() Snapshot
+}
+
+// Snapshot is a copy of a counter's state.
+type Snapshot struct {
+	Value int64
+	// Shards 是每一份各自的值，加起來就是 Value；Mutex 和 Atomic 只有一份
+	Shards []int64
+}
+
+// Mutex is a Counter guarded by a sync.Mutex, as in 206-mutex.
+// The zero value is ready to use.
+type Mutex struct {
+	mu sync.Mutex
+	n  int64
+}
+
+var _ Counter = (*Mutex)(nil)
+
+// NewMutex returns a zero Mutex counter.
+func NewMutex() *Mutex { return &Mutex{} }
+
+func (c *Mutex) Add(delta int64) {
+	c.mu.Lock()
+	c.n += delta
+	c.mu.Unlock()
+}
+
+func (c *Mutex) Load() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.n
+}
+
+func (c *Mutex) Reset() {
+	c.mu.Lock()
+	c.n = 0
+	c.mu.Unlock()
+}
+
+func (c *Mutex) Snapshot() Snapshot {
+	n := c.Load()
+	return Snapshot{Value: n, Shards: []int64{n}}
+}
+
+// Atomic is a Counter backed by atomic.Int64, as in 207-atomic.
+// The zero value is ready to use.
+type Atomic struct {
+	n atomic.Int64
+}
+
+var _ Counter = (*Atomic)(nil)
+
+// NewAtomic returns a zero Atomic counter.
+func NewAtomic() *Atomic { return &Atomic{} }
+
+func (c *Atomic) Add(delta int64) { c.n.Add(delta) }
+
+func (c *Atomic) Load() int64 { return c.n.Load() }
+
+func (c *Atomic) Reset() { c.n.Store(0) }
+
+func (c *Atomic) Snapshot() Snapshot {
+	n := c.n.Load()
+	return Snapshot{Value: n, Shards: []int64{n}}
+}
+
+// cacheLine 是常見 CPU 的快取行大小，每一份補齊到一整行，避免 false sharing
+const cacheLine = 64
+
+type shard struct {
+	n atomic.Int64
+	_ [cacheLine - 8]byte
+}
+
+// Sharded spreads a count over one shard per P so that concurrent Adds
+// rarely touch the same cache line. Load and Snapshot sum the shards, so
+// they are not atomic with respect to concurrent Adds or Reset: a Load
+// that races with Adds sees some of them and not others.
+// Create it with NewSharded; the zero value works but has a single shard,
+// so it behaves like Atomic.
+type Sharded struct {
+	once   sync.Once
+	shards []shard
+	// hints 是 sync.Pool，本身就是每個 P 一份；放的是 shard 的編號，
+	// 同一個 P 上的 goroutine 大多會拿到同一份 shard
+	hints sync.Pool
+	next  atomic.Uint32
+}
+
+var _ Counter = (*Sharded)(nil)
+
+// NewSharded returns a counter with n shards, or GOMAXPROCS shards if
+// n <= 0.
+func NewSharded(n int) *Sharded {
+	if n <= 0 {
+		n = runtime.GOMAXPROCS(0)
+	}
+	c := &Sharded{}
+	c.once.Do(func() { c.setup(n) })
+	return c
+}
+
+func (c *Sharded) setup(n int) {
+	c.shards = make([]shard, n)
+	c.hints.New = func() any {
+		i := int(c.next.Add(1)-1) % len(c.shards)
+		return &i
+	}
+}
+
+// init 讓零值也能用：沒有經過 NewSharded 的話，第一次使用時退回一份 shard
+func (c *Sharded) init() {
+	c.once.Do(func() { c.setup(1) })
+}
+
+func (c *Sharded) Add(delta int64) {
+	c.init()
+	i := c.hints.Get().(*int)
+	c.shards[*i].n.Add(delta)
+	c.hints.Put(i)
+}
+
+func (c *Sharded) Load() int64 {
+	c.init()
+	var sum int64
+	for i := range c.shards {
+		sum += c.shards[i].n.Load()
+	}
+	return sum
+}
+
+func (c *Sharded) Reset() {
+	c.init()
+	for i := range c.shards {
+		c.shards[i].n.Store(0)
+	}
+}
+
+func (c *Sharded) Snapshot() Snapshot {
+	c.init()
+	s := Snapshot{Shards: make([]int64, len(c.shards))}
+	for i := range c.shards {
+		s.Shards[i] = c.shards[i].n.Load()
+		s.Value += s.Shards[i]
+	}
+	return s
+}
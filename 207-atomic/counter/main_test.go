@@ -0,0 +1,172 @@
+package counter
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"testing"
+)
+
+var impls = []struct {
+	name string
+	new  func() Counter
+}{
+	{"Mutex", func() Counter { return NewMutex() }},
+	{"Atomic", func() Counter { return NewAtomic() }},
+	{"Sharded", func() Counter { return NewSharded(0) }},
+}
+
+// 跟 205、206、207 一樣讓 100 個 goroutine 一起加，用 go test -race 跑也不能有 data race
+func TestConcurrentAdd(t *testing.T) {
+	for _, impl := range impls {
+		t.Run(impl.name, func(t *testing.T) {
+			c := impl.new()
+			const gs, adds = 100, 1000
+			var wg sync.WaitGroup
+			wg.Add(gs)
+			for range gs {
+				go func() {
+					defer wg.Done()
+					for range adds {
+						c.Add(1)
+						runtime.Gosched()
+					}
+				}()
+			}
+			// 一邊加一邊讀，確認讀的時候也沒有 race
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				for range 100 {
+					c.Load()
+					c.Snapshot()
+				}
+			}()
+			wg.Wait()
+			<-done
+
+			if x := c.Load(); x != gs*adds {
+				t.Error("Expected", gs*adds, "Got", x)
+			}
+		})
+	}
+}
+
+func TestAddLoadReset(t *testing.T) {
+	for _, impl := range impls {
+		c := impl.new()
+		c.Add(5)
+		c.Add(-2)
+		if x := c.Load(); x != 3 {
+			t.Error(impl.name, "Expected", 3, "Got", x)
+		}
+		s := c.Snapshot()
+		var sum int64
+		for _, v := range s.Shards {
+			sum += v
+		}
+		if s.Value != 3 || sum != 3 {
+			t.Error(impl.name, "Expected snapshot 3 Got", s)
+		}
+		c.Reset()
+		if x := c.Load(); x != 0 {
+			t.Error(impl.name, "Expected", 0, "Got", x)
+		}
+	}
+}
+
+func TestZeroValue(t *testing.T) {
+	var m Mutex
+	var a Atomic
+	var s Sharded
+	for _, c := range []Counter{&m, &a, &s} {
+		c.Add(1)
+		if x := c.Load(); x != 1 {
+			t.Error("Expected", 1, "Got", x)
+		}
+	}
+	// Sharded 的零值只有一份 shard
+	if x := s.Snapshot(); len(x.Shards) != 1 {
+		t.Error("Expected 1 shard Got", x.Shards)
+	}
+}
+
+func TestShards(t *testing.T) {
+	c := NewSharded(4)
+	if x := len(c.Snapshot().Shards); x != 4 {
+		t.Error("Expected", 4, "Got", x)
+	}
+	if x := len(NewSharded(-1).Snapshot().Shards); x != runtime.GOMAXPROCS(0) {
+		t.Error("Expected", runtime.GOMAXPROCS(0), "Got", x)
+	}
+}
+
+// 用 -cpu 掃過不同的 GOMAXPROCS，例如 go test -bench . -cpu 1,2,4,8
+func BenchmarkAdd(b *testing.B) {
+	for _, impl := range impls {
+		b.Run(impl.name, func(b *testing.B) {
+			c := impl.new()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					c.Add(1)
+				}
+			})
+		})
+	}
+}
+
+// 沒有指定 -cpu 的時候自己掃一次 GOMAXPROCS，Sharded 的份數跟著 GOMAXPROCS 變
+func BenchmarkAddProcs(b *testing.B) {
+	for _, procs := range []int{1, 2, 4, 8, 16} {
+		if procs > runtime.NumCPU()*2 {
+			break
+		}
+		for _, impl := range impls {
+			b.Run(fmt.Sprintf("%s/procs=%d", impl.name, procs), func(b *testing.B) {
+				defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(procs))
+				c := impl.new()
+				b.RunParallel(func(pb *testing.PB) {
+					for pb.Next() {
+						c.Add(1)
+					}
+				})
+			})
+		}
+	}
+}
+
+// 寫九次讀一次
+func BenchmarkMixed(b *testing.B) {
+	for _, impl := range impls {
+		b.Run(impl.name, func(b *testing.B) {
+			c := impl.new()
+			b.RunParallel(func(pb *testing.PB) {
+				i := 0
+				for pb.Next() {
+					if i%10 == 0 {
+						c.Load()
+					} else {
+						c.Add(1)
+					}
+					i++
+				}
+			})
+		})
+	}
+}
+
+func ExampleAtomic() {
+	var c Atomic
+	var wg sync.WaitGroup
+	for range 100 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.Add(1)
+		}()
+	}
+	wg.Wait()
+	fmt.Println("count:", c.Load())
+	// Output:
+	// count: 100
+}
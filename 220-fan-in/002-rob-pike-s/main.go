@@ -38,6 +38,7 @@ func boring(msg string) <-chan string {
 }
 
 // fanIn 將兩條只讀 string channel 的輸出匯流到一條新的 channel
+// 可接任意條、會關閉輸出、不留下 goroutine 的版本見 220-fan-in/merge
 func fanIn(input1, input2 <-chan string) <-chan string {
 	c := make(chan string) // 建立無緩衝 channel 作為匯流出口
 
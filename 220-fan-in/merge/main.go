@@ -0,0 +1,254 @@
+// Package merge fans any number of channels into one.
+//
+// 220-fan-in/002-rob-pike-s 的 fanIn 只能接兩條 channel，
+// 兩個 goroutine 用 for { c <- <-input } 永遠不會結束，輸出的 channel 也永遠不會關閉。
+// 這裡的三種合併都會在所有輸入關閉或 ctx 取消後關閉輸出，而且不留下 goroutine：
+//
+//	Merge     固定的 N 條輸入，每條一個 goroutine
+//	Dynamic   執行中可以 Add / Remove 輸入
+//	Priority  同時有多條可以讀時，優先讀 index 小的
+package merge
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+)
+
+// ErrClosed is returned by Dynamic.Add after Close.
+var ErrClosed = errors.New("merge: closed")
+
+// Merge sends every value from chans to the returned channel, which is
+// closed once all of chans are closed or ctx is done.
+// 同一條輸入的值保持原本的順序，不同輸入之間沒有順序保證。
+func Merge[T any](ctx context.Context, chans ...<-chan T) <-chan T {
+	out := make(chan T)
+	var wg sync.WaitGroup
+	wg.Add(len(chans))
+	for _, c := range chans {
+		go func() {
+			defer wg.Done()
+			forward(ctx, c, out, nil)
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+// forward 把 in 的值搬到 out，直到 in 關閉、ctx 取消或 stop 被關閉
+// 因為 stop 而停下時，如果手上還有一個讀出來但沒送出去的值，就回傳它，由呼叫端決定怎麼處理
+func forward[T any](ctx context.Context, in <-chan T, out chan<- T, stop <-chan struct{}) (held T, ok bool) {
+	for {
+		select {
+		case v, open := <-in:
+			if !open {
+				return held, false
+			}
+			select {
+			case out <- v:
+			case <-stop:
+				return v, true
+			case <-ctx.Done():
+				return held, false
+			}
+		case <-stop:
+			return held, false
+		case <-ctx.Done():
+			return held, false
+		}
+	}
+}
+
+// Dynamic merges a set of channels that can change while it runs.
+// The output is closed after Close once every remaining input is closed
+// or removed, or as soon as the context is done.
+type Dynamic[T any] struct {
+	ctx context.Context
+	out chan T
+
+	mu     sync.Mutex
+	inputs map[<-chan T]*input
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// NewDynamic returns an empty Dynamic. Its output stays open until Close
+// or ctx is done, even when it has no inputs.
+func NewDynamic[T any](ctx context.Context) *Dynamic[T] {
+	d := &Dynamic[T]{
+		ctx:    ctx,
+		out:    make(chan T),
+		inputs: map[<-chan T]*input{},
+	}
+	// 佔住一個計數，Close 時才放掉，沒有輸入的時候輸出也不會被關閉
+	d.wg.Add(1)
+	stop := context.AfterFunc(ctx, d.Close)
+	go func() {
+		d.wg.Wait()
+		stop()
+		close(d.out)
+	}()
+	return d
+}
+
+// input 是一條正在讀的輸入：關閉 stop 叫它停，done 關閉表示已經不會再讀
+type input struct {
+	stop, done chan struct{}
+}
+
+// Out returns the merged channel.
+func (d *Dynamic[T]) Out() <-chan T { return d.out }
+
+// Add starts forwarding c. Adding a channel that is already an input does
+// nothing. It returns ErrClosed after Close or once the context is done.
+func (d *Dynamic[T]) Add(c <-chan T) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed || d.ctx.Err() != nil {
+		return ErrClosed
+	}
+	if _, ok := d.inputs[c]; ok {
+		return nil
+	}
+	in := &input{stop: make(chan struct{}), done: make(chan struct{})}
+	d.inputs[c] = in
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		v, held := forward(d.ctx, c, d.out, in.stop)
+		if held {
+			// 已經讀出來的值不能丟，交給另一個 goroutine 送，Remove 就不用等消費者
+			d.wg.Add(1)
+			go func() {
+				defer d.wg.Done()
+				select {
+				case d.out <- v:
+				case <-d.ctx.Done():
+				}
+			}()
+		}
+		d.mu.Lock()
+		// 只刪掉自己；Remove 之後又 Add 同一條 channel 時 map 裡已經是新的 input
+		if d.inputs[c] == in {
+			delete(d.inputs, c)
+		}
+		d.mu.Unlock()
+		close(in.done)
+	}()
+	return nil
+}
+
+// Remove stops reading from c and reports whether c was an input.
+// Once Remove returns nothing more is read from c, but a value read just
+// before may still be delivered.
+func (d *Dynamic[T]) Remove(c <-chan T) bool {
+	d.mu.Lock()
+	in, ok := d.inputs[c]
+	if ok {
+		close(in.stop)
+		delete(d.inputs, c)
+	}
+	d.mu.Unlock()
+	if ok {
+		<-in.done
+	}
+	return ok
+}
+
+// Len returns the number of inputs being read.
+func (d *Dynamic[T]) Len() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.inputs)
+}
+
+// Close stops accepting new inputs. The output is closed once the current
+// inputs are closed or removed. Calling Close more than once is safe.
+func (d *Dynamic[T]) Close() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed {
+		return
+	}
+	d.closed = true
+	d.wg.Done()
+}
+
+// Priority is like Merge, but whenever several inputs have a value ready
+// it takes the one with the lowest index.
+//
+// 只有一個 goroutine 在讀：每一輪先照順序逐條試著讀（不阻塞），
+// 全部都沒有值才用 reflect.Select 一起等。一起等時如果同時有好幾條就緒，選哪一條是隨機的。
+// 另外值讀出來以後要等到有人接收才會讀下一個，所以慢的消費者看到的一直是「讀出當下」的優先順序。
+func Priority[T any](ctx context.Context, chans ...<-chan T) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		inputs := append([]<-chan T(nil), chans...)
+		open := len(inputs)
+		for open > 0 {
+			i, v, ok := first(inputs)
+			if i < 0 {
+				i, v, ok = wait(ctx, inputs)
+				if i < 0 {
+					return
+				}
+			}
+			if !ok {
+				// 關閉的輸入設成 nil，之後的 select 就不會再選到它
+				inputs[i] = nil
+				open--
+				continue
+			}
+			select {
+			case out <- v:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// first 照順序不阻塞地試讀，回傳第一條就緒的 index，沒有就回傳 -1
+func first[T any](inputs []<-chan T) (int, T, bool) {
+	for i, c := range inputs {
+		if c == nil {
+			continue
+		}
+		select {
+		case v, ok := <-c:
+			return i, v, ok
+		default:
+		}
+	}
+	var zero T
+	return -1, zero, false
+}
+
+// wait 阻塞直到任一條輸入就緒，ctx 取消時回傳 -1
+func wait[T any](ctx context.Context, inputs []<-chan T) (int, T, bool) {
+	cases := make([]reflect.SelectCase, len(inputs)+1)
+	cases[0] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())}
+	for i, c := range inputs {
+		cases[i+1] = reflect.SelectCase{Dir: reflect.SelectRecv}
+		if c != nil {
+			cases[i+1].Chan = reflect.ValueOf(c)
+		}
+	}
+	var zero T
+	chosen, rv, ok := reflect.Select(cases)
+	if chosen == 0 {
+		return -1, zero, false
+	}
+	if !ok {
+		return chosen - 1, zero, false
+	}
+	// T 是介面型別而值是 nil 時，Interface() 回傳 nil，斷言失敗正好得到 zero
+	v, _ := rv.Interface().(T)
+	return chosen - 1, v, true
+}
@@ -0,0 +1,211 @@
+package merge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+)
+
+// filled 回傳已經放好 xs 並關閉的 channel
+func filled[T any](xs ...T) <-chan T {
+	c := make(chan T, len(xs))
+	for _, v := range xs {
+		c <- v
+	}
+	close(c)
+	return c
+}
+
+func collect[T any](t *testing.T, c <-chan T) []T {
+	t.Helper()
+	var got []T
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case v, ok := <-c:
+			if !ok {
+				return got
+			}
+			got = append(got, v)
+		case <-timeout:
+			t.Fatal("output not closed, got so far", got)
+		}
+	}
+}
+
+func TestMerge(t *testing.T) {
+	got := collect(t, Merge(context.Background(), filled(1, 2, 3), filled(4, 5), filled[int]()))
+	slices.Sort(got)
+	if fmt.Sprint(got) != "[1 2 3 4 5]" {
+		t.Error("Expected", "[1 2 3 4 5]", "Got", got)
+	}
+
+	// 沒有輸入時立刻關閉
+	if got := collect(t, Merge[int](context.Background())); len(got) != 0 {
+		t.Error("Expected nothing Got", got)
+	}
+}
+
+func TestMergeCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	never := make(chan int)
+	full := filled(1, 2, 3)
+	out := Merge(ctx, never, full)
+	<-out
+	// 一條輸入永遠不關閉、另一條還有值沒人讀，取消後輸出仍然要關閉
+	cancel()
+	collect(t, out)
+}
+
+func TestDynamic(t *testing.T) {
+	d := NewDynamic[int](context.Background())
+	a, b := make(chan int), make(chan int)
+	if err := d.Add(a); err != nil {
+		t.Error("Expected nil Got", err)
+	}
+	d.Add(b)
+	d.Add(a)
+	if d.Len() != 2 {
+		t.Error("Expected", 2, "Got", d.Len())
+	}
+
+	a <- 1
+	if v := <-d.Out(); v != 1 {
+		t.Error("Expected", 1, "Got", v)
+	}
+	if !d.Remove(a) || d.Remove(a) {
+		t.Error("Expected Remove to succeed once")
+	}
+	select {
+	case a <- 2:
+		t.Error("removed input is still being read")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	// Close 之後不能再加，但原本的輸入繼續讀到關閉為止
+	d.Close()
+	d.Close()
+	if err := d.Add(a); !errors.Is(err, ErrClosed) {
+		t.Error("Expected", ErrClosed, "Got", err)
+	}
+	go func() {
+		b <- 3
+		close(b)
+	}()
+	if got := collect(t, d.Out()); fmt.Sprint(got) != "[3]" {
+		t.Error("Expected [3] Got", got)
+	}
+	if d.Len() != 0 {
+		t.Error("Expected", 0, "Got", d.Len())
+	}
+}
+
+func TestDynamicReAdd(t *testing.T) {
+	d := NewDynamic[int](context.Background())
+	a := make(chan int, 1)
+	d.Add(a)
+	d.Remove(a)
+	d.Add(a)
+	a <- 1
+	if v := <-d.Out(); v != 1 {
+		t.Error("Expected", 1, "Got", v)
+	}
+	close(a)
+	d.Close()
+	collect(t, d.Out())
+}
+
+func TestDynamicRemoveHeld(t *testing.T) {
+	d := NewDynamic[int](context.Background())
+	a := make(chan int)
+	d.Add(a)
+	// 值已經被讀出來，卡在等人接收；Remove 不必等消費者，值也不會丟
+	a <- 1
+	removed := make(chan bool)
+	go func() { removed <- d.Remove(a) }()
+	select {
+	case ok := <-removed:
+		if !ok {
+			t.Error("Expected Remove to succeed")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Remove blocked on the consumer")
+	}
+	d.Close()
+	if got := collect(t, d.Out()); fmt.Sprint(got) != "[1]" {
+		t.Error("Expected [1] Got", got)
+	}
+}
+
+func TestDynamicCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	d := NewDynamic[int](ctx)
+	d.Add(make(chan int))
+	cancel()
+	collect(t, d.Out())
+	if err := d.Add(make(chan int)); !errors.Is(err, ErrClosed) {
+		t.Error("Expected", ErrClosed, "Got", err)
+	}
+}
+
+func TestPriority(t *testing.T) {
+	// 三條都已經就緒，一定先讀完 index 小的
+	got := collect(t, Priority(context.Background(), filled("a1", "a2"), filled("b1"), filled("c1", "c2")))
+	if fmt.Sprint(got) != "[a1 a2 b1 c1 c2]" {
+		t.Error("Expected [a1 a2 b1 c1 c2] Got", got)
+	}
+
+	// 都還沒就緒時，等到哪條就讀哪條
+	hi, lo := make(chan string), make(chan string)
+	out := Priority(context.Background(), hi, lo)
+	go func() {
+		lo <- "lo"
+		hi <- "hi"
+		close(lo)
+		close(hi)
+	}()
+	if got := collect(t, out); fmt.Sprint(got) != "[lo hi]" {
+		t.Error("Expected [lo hi] Got", got)
+	}
+
+	// 介面型別的 nil 值
+	if got := collect(t, Priority(context.Background(), filled[error](nil))); len(got) != 1 || got[0] != nil {
+		t.Error("Expected [<nil>] Got", got)
+	}
+}
+
+func TestPriorityCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	out := Priority(ctx, make(chan int), filled(1, 2))
+	<-out
+	cancel()
+	collect(t, out)
+}
+
+func ExampleMerge() {
+	out := Merge(context.Background(), filled("Joe 0", "Joe 1"), filled("Ann 0"))
+	var got []string
+	for v := range out {
+		got = append(got, v)
+	}
+	slices.Sort(got)
+	fmt.Println(got)
+	// Output:
+	// [Ann 0 Joe 0 Joe 1]
+}
+
+func ExamplePriority() {
+	urgent := filled("urgent 0", "urgent 1")
+	normal := filled("normal 0", "normal 1")
+	for v := range Priority(context.Background(), urgent, normal) {
+		fmt.Println(v)
+	}
+	// Output:
+	// urgent 0
+	// urgent 1
+	// normal 0
+	// normal 1
+}
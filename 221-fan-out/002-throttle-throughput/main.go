@@ -38,6 +38,7 @@ func populate(c chan int) {
 
 // fanOutIn 使用固定數量的 worker goroutine 並行消費 c1
 // 並將處理結果寫入 c2，所有工作完成後再關閉 c2
+// 泛型、可取消、可設逾時和保留順序的版本見 221-fan-out/pool
 func fanOutIn(c1, c2 chan int) {
 	var wg sync.WaitGroup
 	const goroutines = 3    // 定義並行 worker 的數量
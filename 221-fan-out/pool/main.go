@@ -0,0 +1,232 @@
+// Package pool is a generic version of fanOutIn from
+// 221-fan-out/002-throttle-throughput.
+//
+// fanOutIn 把 worker 數量（const goroutines = 3）、型別（chan int）和工作（timeConsumingWork）都寫死了。
+// Pool[In, Out] 把它們變成參數，另外加上：
+//   - ctx 取消：不再讀新的輸入，還在跑的工作拿到被取消的 ctx，輸出 channel 一定會關閉
+//   - 每個工作的逾時（TaskTimeout）
+//   - 錯誤跟結果一起放在 Result 裡，從同一個 channel 出來
+//   - Ordered：照輸入的順序輸出
+//   - Stats：排隊中、忙碌中的 worker 數量
+package pool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrTaskTimeout is wrapped by Result.Err when a task ran past
+// Options.TaskTimeout.
+var ErrTaskTimeout = errors.New("pool: task timed out")
+
+// Func processes one input. It should return promptly when ctx is done.
+type Func[In, Out any] func(ctx context.Context, in In) (Out, error)
+
+// Options configures a Pool. The zero value runs GOMAXPROCS workers,
+// unordered, without a per-task timeout.
+type Options struct {
+	Workers     int           // worker 數量，<= 0 表示 GOMAXPROCS
+	QueueSize   int           // 已經讀進來、還沒有 worker 接手的工作最多幾個
+	TaskTimeout time.Duration // 0 表示不限
+	Ordered     bool          // 照輸入順序輸出；慢的工作會擋住後面已經做完的結果
+}
+
+// Result is the outcome of one input.
+type Result[In, Out any] struct {
+	Index int // 第幾個輸入，從 0 開始
+	In    In
+	Out   Out
+	Err   error
+}
+
+// Stats is a snapshot of a pool's activity across all runs.
+type Stats struct {
+	Queued int64 // 已經讀進來、等 worker 的工作
+	Busy   int64 // 正在執行 Func 的 worker
+	Done   int64 // 做完的工作，包含失敗的
+	Failed int64 // Err 不是 nil 的工作
+}
+
+// Pool runs a Func on many inputs with a fixed number of workers.
+type Pool[In, Out any] struct {
+	fn   Func[In, Out]
+	opts Options
+
+	queued, busy, done, failed atomic.Int64
+}
+
+// New returns a pool that applies fn.
+func New[In, Out any](fn Func[In, Out], opts Options) *Pool[In, Out] {
+	if opts.Workers <= 0 {
+		opts.Workers = runtime.GOMAXPROCS(0)
+	}
+	if opts.QueueSize < 0 {
+		opts.QueueSize = 0
+	}
+	return &Pool[In, Out]{fn: fn, opts: opts}
+}
+
+// Stats returns the current counters.
+func (p *Pool[In, Out]) Stats() Stats {
+	return Stats{
+		Queued: p.queued.Load(),
+		Busy:   p.busy.Load(),
+		Done:   p.done.Load(),
+		Failed: p.failed.Load(),
+	}
+}
+
+type task[In any] struct {
+	index int
+	in    In
+}
+
+// Run reads in until it is closed or ctx is done and sends one Result
+// per input read. The returned channel is closed after the last result.
+// 呼叫端要一直讀到 channel 關閉，或取消 ctx；否則 worker 會卡在送出結果。
+func (p *Pool[In, Out]) Run(ctx context.Context, in <-chan In) <-chan Result[In, Out] {
+	tasks := make(chan task[In], p.opts.QueueSize)
+	results := make(chan Result[In, Out])
+
+	// 分派：幫每個輸入編號
+	go func() {
+		defer close(tasks)
+		for i := 0; ; i++ {
+			var v In
+			var ok bool
+			select {
+			case v, ok = <-in:
+			case <-ctx.Done():
+				return
+			}
+			if !ok {
+				return
+			}
+			p.queued.Add(1)
+			select {
+			case tasks <- task[In]{i, v}:
+			case <-ctx.Done():
+				p.queued.Add(-1)
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	wg.Add(p.opts.Workers)
+	for range p.opts.Workers {
+		go func() {
+			defer wg.Done()
+			for t := range tasks {
+				p.queued.Add(-1)
+				r := p.do(ctx, t)
+				select {
+				case results <- r:
+				case <-ctx.Done():
+					// 沒有人讀了，剩下的工作照樣從 tasks 拿出來丟掉，讓分派的 goroutine 結束
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	if !p.opts.Ordered {
+		return results
+	}
+	return reorder(ctx, results)
+}
+
+// do 執行一個工作，加上逾時和統計
+func (p *Pool[In, Out]) do(ctx context.Context, t task[In]) Result[In, Out] {
+	r := Result[In, Out]{Index: t.index, In: t.in}
+	if err := ctx.Err(); err != nil {
+		r.Err = err
+	} else {
+		p.busy.Add(1)
+		tctx, cancel := ctx, context.CancelFunc(func() {})
+		if p.opts.TaskTimeout > 0 {
+			tctx, cancel = context.WithTimeout(ctx, p.opts.TaskTimeout)
+		}
+		r.Out, r.Err = p.fn(tctx, t.in)
+		// Func 沒理會 ctx 而晚回來時，一樣算逾時
+		if ctx.Err() == nil && errors.Is(tctx.Err(), context.DeadlineExceeded) {
+			r.Err = fmt.Errorf("%w after %v: %w", ErrTaskTimeout, p.opts.TaskTimeout, context.DeadlineExceeded)
+		}
+		cancel()
+		p.busy.Add(-1)
+	}
+	p.done.Add(1)
+	if r.Err != nil {
+		p.failed.Add(1)
+	}
+	return r
+}
+
+// reorder 把結果按照 Index 排好再送出
+func reorder[In, Out any](ctx context.Context, results <-chan Result[In, Out]) <-chan Result[In, Out] {
+	out := make(chan Result[In, Out])
+	go func() {
+		defer close(out)
+		pending := map[int]Result[In, Out]{}
+		next := 0
+		for r := range results {
+			pending[r.Index] = r
+			for {
+				r, ok := pending[next]
+				if !ok {
+					break
+				}
+				select {
+				case out <- r:
+				case <-ctx.Done():
+					// 繼續把 results 讀完，讓 worker 可以結束
+					for range results {
+					}
+					return
+				}
+				delete(pending, next)
+				next++
+			}
+		}
+	}()
+	return out
+}
+
+// Map runs fn over ins in order and returns the outputs in the same
+// order, with every error joined.
+func Map[In, Out any](ctx context.Context, fn Func[In, Out], opts Options, ins []In) ([]Out, error) {
+	in := make(chan In)
+	go func() {
+		defer close(in)
+		for _, v := range ins {
+			select {
+			case in <- v:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	outs := make([]Out, len(ins))
+	var errs []error
+	seen := 0
+	for r := range New(fn, opts).Run(ctx, in) {
+		seen++
+		outs[r.Index] = r.Out
+		if r.Err != nil {
+			errs = append(errs, fmt.Errorf("input %d: %w", r.Index, r.Err))
+		}
+	}
+	if seen < len(ins) {
+		errs = append(errs, ctx.Err())
+	}
+	return outs, errors.Join(errs...)
+}
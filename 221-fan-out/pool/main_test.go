@@ -0,0 +1,190 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func feed[T any](xs ...T) <-chan T {
+	c := make(chan T)
+	go func() {
+		defer close(c)
+		for _, v := range xs {
+			c <- v
+		}
+	}()
+	return c
+}
+
+func square(_ context.Context, v int) (int, error) { return v * v, nil }
+
+func TestRunUnordered(t *testing.T) {
+	p := New(square, Options{Workers: 3})
+	sum, n := 0, 0
+	for r := range p.Run(context.Background(), feed(0, 1, 2, 3, 4, 5, 6, 7, 8, 9)) {
+		if r.Err != nil || r.Out != r.In*r.In || r.In != r.Index {
+			t.Error("Unexpected result", r)
+		}
+		sum += r.Out
+		n++
+	}
+	if n != 10 || sum != 285 {
+		t.Error("Expected", 10, 285, "Got", n, sum)
+	}
+	if s := p.Stats(); s != (Stats{Done: 10}) {
+		t.Error("Expected", Stats{Done: 10}, "Got", s)
+	}
+}
+
+func TestRunOrdered(t *testing.T) {
+	// 越前面的輸入做得越久，不排序的話一定會亂掉
+	slow := func(ctx context.Context, v int) (int, error) {
+		time.Sleep(time.Duration(10-v) * time.Millisecond)
+		return v, nil
+	}
+	p := New(slow, Options{Workers: 5, Ordered: true})
+	i := 0
+	for r := range p.Run(context.Background(), feed(0, 1, 2, 3, 4, 5, 6, 7, 8, 9)) {
+		if r.Index != i || r.Out != i {
+			t.Error("Expected", i, "Got", r.Index, r.Out)
+		}
+		i++
+	}
+	if i != 10 {
+		t.Error("Expected", 10, "Got", i)
+	}
+}
+
+func TestErrorsAndTimeout(t *testing.T) {
+	errOdd := errors.New("odd")
+	fn := func(ctx context.Context, v int) (int, error) {
+		switch {
+		case v == 4:
+			<-ctx.Done()
+			return 0, ctx.Err()
+		case v == 5:
+			// 不理會 ctx，晚回來也算逾時
+			time.Sleep(30 * time.Millisecond)
+			return v, nil
+		case v%2 == 1:
+			return 0, errOdd
+		}
+		return v, nil
+	}
+	p := New(fn, Options{Workers: 2, TaskTimeout: 10 * time.Millisecond, Ordered: true})
+	want := []error{nil, errOdd, nil, errOdd, ErrTaskTimeout, ErrTaskTimeout}
+	i := 0
+	for r := range p.Run(context.Background(), feed(0, 1, 2, 3, 4, 5)) {
+		if !errors.Is(r.Err, want[i]) || (want[i] == nil && r.Err != nil) {
+			t.Error("Input", r.In, "Expected", want[i], "Got", r.Err)
+		}
+		if want[i] == ErrTaskTimeout && !errors.Is(r.Err, context.DeadlineExceeded) {
+			t.Error("Expected DeadlineExceeded Got", r.Err)
+		}
+		i++
+	}
+	if s := p.Stats(); s.Done != 6 || s.Failed != 4 {
+		t.Error("Expected", 6, 4, "Got", s.Done, s.Failed)
+	}
+}
+
+func TestCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var started atomic.Int64
+	block := func(ctx context.Context, v int) (int, error) {
+		started.Add(1)
+		<-ctx.Done()
+		return 0, ctx.Err()
+	}
+	// 輸入永遠不會關閉
+	in := make(chan int)
+	go func() {
+		for i := 0; ; i++ {
+			select {
+			case in <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for _, ordered := range []bool{false, true} {
+		ctx, cancel := context.WithCancel(ctx)
+		p := New(block, Options{Workers: 4, QueueSize: 2, Ordered: ordered})
+		results := p.Run(ctx, in)
+		for p.Stats().Busy < 4 || p.Stats().Queued < 2 {
+			time.Sleep(time.Millisecond)
+		}
+		if s := p.Stats(); s.Busy != 4 || s.Queued < 2 {
+			t.Error("Expected 4 busy and queued >= 2 Got", s)
+		}
+		// 不讀結果就取消，channel 也要關閉
+		cancel()
+		select {
+		case <-drain(results):
+		case <-time.After(time.Second):
+			t.Fatal("results not closed after cancel, ordered =", ordered)
+		}
+		if s := p.Stats(); s.Busy != 0 || s.Queued != 0 {
+			t.Error("Expected idle pool Got", s)
+		}
+	}
+	cancel()
+}
+
+func drain[T any](c <-chan T) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		for range c {
+		}
+		close(done)
+	}()
+	return done
+}
+
+func TestMap(t *testing.T) {
+	outs, err := Map(context.Background(), square, Options{Workers: 2}, []int{1, 2, 3})
+	if err != nil || fmt.Sprint(outs) != "[1 4 9]" {
+		t.Error("Expected [1 4 9] Got", outs, err)
+	}
+
+	boom := errors.New("boom")
+	_, err = Map(context.Background(), func(_ context.Context, v int) (int, error) {
+		if v == 2 {
+			return 0, boom
+		}
+		return v, nil
+	}, Options{}, []int{1, 2, 3})
+	if !errors.Is(err, boom) {
+		t.Error("Expected", boom, "Got", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := Map(ctx, square, Options{}, []int{1, 2, 3}); !errors.Is(err, context.Canceled) {
+		t.Error("Expected", context.Canceled, "Got", err)
+	}
+}
+
+func ExamplePool_Run() {
+	p := New(func(_ context.Context, s string) (int, error) {
+		if s == "" {
+			return 0, errors.New("empty")
+		}
+		return len(s), nil
+	}, Options{Workers: 3, Ordered: true})
+
+	for r := range p.Run(context.Background(), feed("fan", "", "out")) {
+		fmt.Println(r.Index, r.In, r.Out, r.Err)
+	}
+	fmt.Printf("%+v\n", p.Stats())
+	// Output:
+	// 0 fan 3 <nil>
+	// 1  0 empty
+	// 2 out 3 <nil>
+	// {Queued:0 Busy:0 Done:3 Failed:1}
+}
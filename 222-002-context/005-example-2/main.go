@@ -26,6 +26,7 @@ func main() {
 }
 
 // gen 接受一個 Context，回傳只讀的 int channel：<-chan int
+// 泛型的版本（Generate、Map、Filter、Batch、Take...）見 222-002-context/pipeline
 func gen(ctx context.Context) <-chan int {
     dst := make(chan int) // 建立無緩衝 channel，供外部接收
     n := 1                // 從 1 開始計數
@@ -0,0 +1,287 @@
+// Package pipeline provides generic, context-aware channel stages.
+//
+// 課程裡的 pipeline 都是手接的：populate → fanOutIn、005-example-2 的 gen(ctx)、
+// 220-fan-in/001-todd-s 的 send / receive。這裡把常見的階段做成泛型函式，
+// 每個階段都回傳只讀的 channel，可以直接接到下一個階段：
+//
+//	ctx, cancel := context.WithCancel(context.Background())
+//	defer cancel()
+//	nums := pipeline.Generate(ctx, pipeline.Count(1))
+//	even := pipeline.Filter(ctx, nums, func(v int) bool { return v%2 == 0 })
+//	for v := range pipeline.Take(ctx, even, 5) {
+//		fmt.Println(v)
+//	}
+//
+// 規則跟 005-example-2 一樣：每個階段在輸入關閉或 ctx 取消時關閉自己的輸出並結束 goroutine。
+// 消費者提早離開（break、Take）時上游會卡在送值，所以一定要 cancel ctx，
+// 通常就是 defer cancel()。
+package pipeline
+
+import (
+	"context"
+	"iter"
+	"math"
+	"reflect"
+	"time"
+)
+
+// send 把 v 送到 out，ctx 先取消就回傳 false
+func send[T any](ctx context.Context, out chan<- T, v T) bool {
+	select {
+	case out <- v:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// recv 從 in 讀一個值，in 關閉或 ctx 取消就回傳 false
+func recv[T any](ctx context.Context, in <-chan T) (T, bool) {
+	select {
+	case v, ok := <-in:
+		return v, ok
+	case <-ctx.Done():
+		var zero T
+		return zero, false
+	}
+}
+
+// Generate sends every value of seq and closes the output when seq ends.
+// seq stops being pulled as soon as ctx is done.
+func Generate[T any](ctx context.Context, seq iter.Seq[T]) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for v := range seq {
+			if !send(ctx, out, v) {
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// Count returns the endless sequence start, start+1, start+2, ...
+// like gen in 005-example-2.
+func Count(start int) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for n := start; yield(n); n++ {
+		}
+	}
+}
+
+// Map sends f(v) for every v read from in.
+func Map[In, Out any](ctx context.Context, in <-chan In, f func(In) Out) <-chan Out {
+	out := make(chan Out)
+	go func() {
+		defer close(out)
+		for {
+			v, ok := recv(ctx, in)
+			if !ok || !send(ctx, out, f(v)) {
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// Filter sends the values read from in for which keep returns true.
+func Filter[T any](ctx context.Context, in <-chan T, keep func(T) bool) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for {
+			v, ok := recv(ctx, in)
+			if !ok {
+				return
+			}
+			if keep(v) && !send(ctx, out, v) {
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// Batch groups values into slices of size values. A batch is also sent
+// when maxWait has passed since its first value, and the last partial
+// batch is sent when in is closed. maxWait <= 0 means wait for a full batch.
+// size < 1 is treated as 1.
+func Batch[T any](ctx context.Context, in <-chan T, size int, maxWait time.Duration) <-chan []T {
+	size = max(size, 1)
+	out := make(chan []T)
+	go func() {
+		defer close(out)
+		var (
+			batch []T
+			timer *time.Timer
+			fire  <-chan time.Time // 沒有未滿的 batch 時是 nil，select 不會選到
+		)
+		defer func() {
+			if timer != nil {
+				timer.Stop()
+			}
+		}()
+		flush := func() bool {
+			if timer != nil {
+				timer.Stop()
+			}
+			fire = nil
+			b := batch
+			batch = nil
+			return send(ctx, out, b)
+		}
+
+		for {
+			select {
+			case v, ok := <-in:
+				if !ok {
+					if len(batch) > 0 {
+						flush()
+					}
+					return
+				}
+				batch = append(batch, v)
+				if len(batch) == 1 && maxWait > 0 {
+					// Go 1.23 起 Reset 後不會再收到舊的時間，不用先把 channel 清空
+					if timer == nil {
+						timer = time.NewTimer(maxWait)
+					} else {
+						timer.Reset(maxWait)
+					}
+					fire = timer.C
+				}
+				if len(batch) == size && !flush() {
+					return
+				}
+			case <-fire:
+				if !flush() {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// Tee copies every value read from in to n outputs. The next value is
+// read only after every output has received the current one, so the
+// slowest consumer sets the pace. n < 0 is treated as 0, in which case
+// in is drained and nothing is returned.
+func Tee[T any](ctx context.Context, in <-chan T, n int) []<-chan T {
+	n = max(n, 0)
+	outs := make([]chan T, n)
+	ro := make([]<-chan T, n)
+	for i := range outs {
+		outs[i] = make(chan T)
+		ro[i] = outs[i]
+	}
+	go func() {
+		defer func() {
+			for _, c := range outs {
+				close(c)
+			}
+		}()
+		// cases[0] 是 ctx.Done()，後面每個輸出一個
+		cases := make([]reflect.SelectCase, n+1)
+		cases[0] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())}
+		for {
+			v, ok := recv(ctx, in)
+			if !ok {
+				return
+			}
+			// 誰先準備好就先給誰，不然照順序送的話，先讀 outs[1] 的消費者會跟 outs[0] 互相卡住
+			rv := reflect.ValueOf(&v).Elem()
+			for i, c := range outs {
+				cases[i+1] = reflect.SelectCase{Dir: reflect.SelectSend, Chan: reflect.ValueOf(c), Send: rv}
+			}
+			for range n {
+				chosen, _, _ := reflect.Select(cases)
+				if chosen == 0 {
+					return
+				}
+				// 送過的輸出換成零值 Chan，reflect.Select 會忽略它
+				cases[chosen].Chan = reflect.Value{}
+			}
+		}
+	}()
+	return ro
+}
+
+// Throttle forwards values from in at most rate per second, spaced evenly.
+// rate <= 0 or NaN means no limit. Rates so small that the interval
+// does not fit in a time.Duration are clamped to the longest Duration.
+func Throttle[T any](ctx context.Context, in <-chan T, rate float64) <-chan T {
+	if !(rate > 0) {
+		return Map(ctx, in, func(v T) T { return v })
+	}
+	// 很小的 rate 直接轉成 Duration 會溢位變成負數，等於沒有限速
+	interval := time.Duration(math.MaxInt64)
+	if secs := float64(time.Second) / rate; secs < float64(math.MaxInt64) {
+		interval = time.Duration(secs)
+	}
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		// 第一個值不用等；之後每個值至少跟上一個差 interval
+		var last time.Time
+		for {
+			v, ok := recv(ctx, in)
+			if !ok {
+				return
+			}
+			if wait := time.Until(last.Add(interval)); !last.IsZero() && wait > 0 {
+				t := time.NewTimer(wait)
+				select {
+				case <-t.C:
+				case <-ctx.Done():
+					t.Stop()
+					return
+				}
+			}
+			if !send(ctx, out, v) {
+				return
+			}
+			last = time.Now()
+		}
+	}()
+	return out
+}
+
+// Take forwards the first n values from in and then closes its output.
+// It stops reading after n values, so the stages before it stay blocked
+// until ctx is cancelled.
+func Take[T any](ctx context.Context, in <-chan T, n int) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for range n {
+			v, ok := recv(ctx, in)
+			if !ok || !send(ctx, out, v) {
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// Drain reads and discards values from in until it is closed or ctx is
+// done, returning the number of values read and ctx.Err().
+// 上游的階段在 ctx 取消時也會關閉輸出，所以 in 關閉時還是要看 ctx，才知道資料是不是完整的。
+// 用在只關心副作用、不關心結果的 pipeline 尾端。
+func Drain[T any](ctx context.Context, in <-chan T) (int, error) {
+	n := 0
+	for {
+		select {
+		case _, ok := <-in:
+			if !ok {
+				return n, ctx.Err()
+			}
+			n++
+		case <-ctx.Done():
+			return n, ctx.Err()
+		}
+	}
+}
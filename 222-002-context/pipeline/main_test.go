@@ -0,0 +1,198 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func collect[T any](c <-chan T) []T {
+	var got []T
+	for v := range c {
+		got = append(got, v)
+	}
+	return got
+}
+
+// noLeak 在測試結束時檢查 goroutine 數量回到開始時的樣子
+func noLeak(t *testing.T) {
+	t.Helper()
+	before := runtime.NumGoroutine()
+	t.Cleanup(func() {
+		deadline := time.Now().Add(time.Second)
+		for runtime.NumGoroutine() > before {
+			if time.Now().After(deadline) {
+				buf := make([]byte, 1<<16)
+				t.Error("Expected", before, "goroutines Got", runtime.NumGoroutine(), "\n", string(buf[:runtime.Stack(buf, true)]))
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}
+	})
+}
+
+func TestStages(t *testing.T) {
+	noLeak(t)
+	ctx := context.Background()
+	nums := Generate(ctx, slices.Values([]int{1, 2, 3, 4, 5, 6}))
+	odd := Filter(ctx, nums, func(v int) bool { return v%2 == 1 })
+	strs := Map(ctx, odd, strconv.Itoa)
+	if got := collect(strs); fmt.Sprint(got) != "[1 3 5]" {
+		t.Error("Expected [1 3 5] Got", got)
+	}
+
+	n, err := Drain(ctx, Take(ctx, Generate(ctx, slices.Values([]int{1, 2})), 5))
+	if n != 2 || err != nil {
+		t.Error("Expected", 2, nil, "Got", n, err)
+	}
+}
+
+// 跟 005-example-2 一樣讀到 5 就 break，cancel 之後每一段都要結束
+func TestEarlyStop(t *testing.T) {
+	noLeak(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	src := Map(ctx, Generate(ctx, Count(1)), func(v int) int { return v })
+	tees := Tee(ctx, src, 2)
+	go Drain(ctx, tees[1])
+	batches := Batch(ctx, Throttle(ctx, Filter(ctx, tees[0], func(int) bool { return true }), 1e6), 2, time.Second)
+	for b := range batches {
+		if b[1] >= 6 {
+			break
+		}
+	}
+	cancel()
+
+	// Take 拿完就關閉，上游靠 cancel 結束
+	ctx, cancel = context.WithCancel(context.Background())
+	if got := collect(Take(ctx, Generate(ctx, Count(1)), 3)); fmt.Sprint(got) != "[1 2 3]" {
+		t.Error("Expected [1 2 3] Got", got)
+	}
+	cancel()
+
+	// 下游根本沒人讀
+	ctx, cancel = context.WithCancel(context.Background())
+	Batch(ctx, Generate(ctx, Count(1)), 3, 0)
+	Tee(ctx, Generate(ctx, Count(1)), 3)
+	cancel()
+}
+
+func TestBatch(t *testing.T) {
+	noLeak(t)
+	ctx := context.Background()
+	got := collect(Batch(ctx, Generate(ctx, slices.Values([]int{1, 2, 3, 4, 5})), 2, 0))
+	if fmt.Sprint(got) != "[[1 2] [3 4] [5]]" {
+		t.Error("Expected [[1 2] [3 4] [5]] Got", got)
+	}
+
+	// 等太久就先送出未滿的 batch
+	in := make(chan int)
+	out := Batch(ctx, in, 3, 20*time.Millisecond)
+	in <- 1
+	in <- 2
+	start := time.Now()
+	if b := <-out; fmt.Sprint(b) != "[1 2]" {
+		t.Error("Expected [1 2] Got", b)
+	}
+	if d := time.Since(start); d > 500*time.Millisecond {
+		t.Error("maxWait not honoured, waited", d)
+	}
+	in <- 3
+	in <- 4
+	in <- 5
+	if b := <-out; fmt.Sprint(b) != "[3 4 5]" {
+		t.Error("Expected [3 4 5] Got", b)
+	}
+	close(in)
+	if b, ok := <-out; ok {
+		t.Error("Expected closed Got", b)
+	}
+
+	if got := collect(Batch(ctx, Generate(ctx, slices.Values([]int{1, 2})), 0, 0)); len(got) != 2 {
+		t.Error("Expected size 1 batches Got", got)
+	}
+}
+
+func TestTee(t *testing.T) {
+	noLeak(t)
+	ctx := context.Background()
+	outs := Tee(ctx, Generate(ctx, slices.Values([]error{nil, errors.New("x")})), 3)
+	got := make([][]error, 3)
+	var wg sync.WaitGroup
+	// 倒過來啟動讀者，照順序送的話會卡住
+	for i := 2; i >= 0; i-- {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got[i] = collect(outs[i])
+		}()
+	}
+	wg.Wait()
+	for i, g := range got {
+		if fmt.Sprint(g) != "[<nil> x]" {
+			t.Error("Output", i, "Expected [<nil> x] Got", g)
+		}
+	}
+
+	// n < 0 跟 0 一樣：沒有輸出，輸入照樣讀完
+	in := Generate(ctx, slices.Values([]int{1, 2}))
+	if outs := Tee(ctx, in, -1); len(outs) != 0 {
+		t.Error("Expected no outputs Got", len(outs))
+	}
+}
+
+func TestThrottle(t *testing.T) {
+	noLeak(t)
+	ctx := context.Background()
+	start := time.Now()
+	got := collect(Throttle(ctx, Generate(ctx, slices.Values([]int{1, 2, 3, 4, 5})), 100))
+	// 第一個不用等，後面四個各至少 10ms
+	if d := time.Since(start); d < 40*time.Millisecond {
+		t.Error("Expected at least 40ms Got", d)
+	}
+	if fmt.Sprint(got) != "[1 2 3 4 5]" {
+		t.Error("Expected [1 2 3 4 5] Got", got)
+	}
+
+	if got := collect(Throttle(ctx, Generate(ctx, slices.Values([]int{1, 2})), 0)); fmt.Sprint(got) != "[1 2]" {
+		t.Error("Expected [1 2] Got", got)
+	}
+
+	// 間隔超過 Duration 的範圍時要一直等，不能溢位成負數而不限速
+	cctx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if got := collect(Throttle(cctx, Generate(cctx, slices.Values([]int{1, 2})), 1e-12)); fmt.Sprint(got) != "[1]" {
+		t.Error("Expected [1] Got", got)
+	}
+}
+
+func TestDrainCancel(t *testing.T) {
+	noLeak(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	n, err := Drain(ctx, Generate(ctx, Count(1)))
+	if !errors.Is(err, context.Canceled) || n == 0 {
+		t.Error("Expected", context.Canceled, "Got", n, err)
+	}
+}
+
+func Example() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	nums := Generate(ctx, Count(1))
+	even := Filter(ctx, nums, func(v int) bool { return v%2 == 0 })
+	squares := Map(ctx, even, func(v int) int { return v * v })
+	for b := range Batch(ctx, Take(ctx, squares, 5), 2, 0) {
+		fmt.Println(b)
+	}
+	// Output:
+	// [4 16]
+	// [36 64]
+	// [100]
+}
@@ -3,12 +3,19 @@ package main
 import (
 	"log"
 	"os"
+
+	"github.com/andyrestart9/animalPackage/232-printing-and-logging/logger"
 )
 
 func main() {
 	f, err := os.Open("xx.txt")
 	if err != nil {
 		log.Println("err happened:", err) // 2025/05/21 19:38:36 err happened: open xx.txt: no such file or directory
+
+		// 有等級和欄位的版本，LOG_FORMAT=json 會印成 JSON
+		if l, lerr := logger.FromEnv(os.Stdout); lerr == nil {
+			l.Error("err happened", "file", "xx.txt", "err", err) // time=2025-05-21T19:38:36.000+08:00 level=ERROR msg="err happened" file=xx.txt err="open xx.txt: no such file or directory"
+		}
 		return
 	}
 	defer f.Close()
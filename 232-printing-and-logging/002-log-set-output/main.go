@@ -4,19 +4,32 @@ import (
 	"fmt" // 提供格式化輸出功能，用於在終端顯示錯誤
 	"log" // 提供日誌功能，這裡用於將錯誤寫入檔案
 	"os"  // 提供作業系統功能，用於檔案的建立、開啟與關閉
+
+	"github.com/andyrestart9/animalPackage/232-printing-and-logging/002-log-set-output/rotate"
 )
 
 func main() {
-	// 建立（或覆寫）log.txt 檔案，若檔案不存在則建立，存在則清空
-	f, err := os.Create("log.txt")
+	// 原本用 os.Create("log.txt")，檔案會一直長大。
+	// rotate.Open 以附加模式開啟 log.txt，超過 1 MB 就把舊的搬成 log-時間.txt.gz，最多留 5 個
+	f, err := rotate.Open("log.txt", rotate.Options{
+		MaxSize:    1 << 20,
+		MaxBackups: 5,
+		Compress:   true,
+	})
 	if err != nil {
 		// 如果建立檔案失敗（例如：權限不足、磁碟滿），就在終端印出錯誤
 		fmt.Println(err)
+		return
 	}
-	// 延遲關閉 log.txt，確保 main 結束前檔案一定會被關閉
+	// 延遲關閉 log.txt，確保 main 結束前檔案一定會被關閉，背景的壓縮也會做完
 	defer f.Close()
 
+	// 外部的 logrotate 搬走 log.txt 之後送 SIGHUP，就會重新開啟新的 log.txt
+	stop := f.ReopenOnSignal(func(err error) { fmt.Println(err) })
+	defer stop()
+
 	// 將標準日誌輸出導向到 log.txt，之後呼叫 log.Println 都會寫入此檔
+	// rotate.Writer 可以同時被多個 goroutine 寫入
 	log.SetOutput(f)
 
 	// 嘗試以唯讀模式開啟 xx.txt，只取回錯誤值
@@ -0,0 +1,376 @@
+// Package rotate provides an io.Writer for log.SetOutput that rotates the
+// log file by size and by time and keeps a bounded number of backups.
+//
+// 002-log-set-output 把 log 導向單一個 log.txt，檔案會一直長大。Writer 會：
+//   - 檔案超過 MaxSize 或開啟超過 RotateEvery 就換一個新檔
+//   - 舊檔改名成 log-20250102T150405.000.txt（UTC），可以選擇壓縮成 .gz
+//   - 只留 MaxBackups 個備份，比 MaxAge 舊的也刪掉
+//
+// 跟外部的 logrotate 一起用的時候，logrotate 把檔案搬走之後送 SIGHUP，
+// ReopenOnSignal 會重新開啟同名的新檔。
+//
+// Writer 可以同時被多個 goroutine 使用。
+package rotate
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// TimeFormat is the UTC timestamp inserted into backup names.
+// 不用冒號，Windows 的檔名不能有冒號。
+const TimeFormat = "20060102T150405.000"
+
+// ErrClosed is returned by Write after Close.
+var ErrClosed = errors.New("rotate: writer closed")
+
+// Options configures a Writer. Zero fields disable the corresponding limit.
+type Options struct {
+	MaxSize     int64         // 位元組，超過就換檔；一次寫入比 MaxSize 還大時照寫，不會切開
+	RotateEvery time.Duration // 檔案開啟多久之後換檔
+	MaxBackups  int           // 最多留幾個備份
+	MaxAge      time.Duration // 備份留多久
+	Compress    bool          // 備份壓縮成 .gz
+	Perm        fs.FileMode   // 新檔的權限，預設 0644
+
+	// Now 給測試用，nil 表示 time.Now
+	Now func() time.Time
+}
+
+// Writer is a rotating log file. Create it with Open.
+type Writer struct {
+	path string
+	opts Options
+
+	mu     sync.Mutex
+	f      *os.File
+	size   int64
+	opened time.Time
+	closed bool
+
+	// 壓縮和清理在背景做，不擋住寫入；millMu 讓它們一次只跑一個
+	millMu sync.Mutex
+	wg     sync.WaitGroup
+	// millErr 收集背景工作的錯誤，由 Close 回報；讀寫都要拿 millMu
+	millErr error
+}
+
+// Open opens path for appending, creating it if needed.
+func Open(path string, opts Options) (*Writer, error) {
+	if opts.Perm == 0 {
+		opts.Perm = 0o644
+	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	w := &Writer{path: path, opts: opts}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// open 開啟 w.path，呼叫前要拿著 w.mu（Open 除外）
+func (w *Writer) open() error {
+	f, err := os.OpenFile(w.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, w.opts.Perm)
+	if err != nil {
+		return err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	w.f, w.size, w.opened = f, fi.Size(), w.opts.Now()
+	return nil
+}
+
+// Write writes p to the current file, rotating first if p would push the
+// file past MaxSize or the file is older than RotateEvery.
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return 0, ErrClosed
+	}
+
+	tooBig := w.opts.MaxSize > 0 && w.size > 0 && w.size+int64(len(p)) > w.opts.MaxSize
+	tooOld := w.opts.RotateEvery > 0 && w.opts.Now().Sub(w.opened) >= w.opts.RotateEvery
+	if tooBig || tooOld {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := w.f.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// Rotate moves the current file to a backup and starts a new one.
+func (w *Writer) Rotate() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return ErrClosed
+	}
+	return w.rotate()
+}
+
+func (w *Writer) rotate() error {
+	if err := w.f.Close(); err != nil {
+		// w.f 已經不能用了，重新打開同一個檔案，之後的 Write 才不會一直失敗
+		return errors.Join(err, w.open())
+	}
+	if err := os.Rename(w.path, w.backupName(w.opts.Now())); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		// 改名失敗就繼續寫原本的檔案，不要把 log 弄丟
+		return errors.Join(err, w.open())
+	}
+	if err := w.open(); err != nil {
+		return err
+	}
+	w.mill()
+	return nil
+}
+
+// Reopen closes the file and opens path again without renaming anything.
+// 外部的 logrotate 已經把檔案搬走時用這個。
+func (w *Writer) Reopen() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return ErrClosed
+	}
+	cerr := w.f.Close()
+	return errors.Join(cerr, w.open())
+}
+
+// ReopenOnSignal calls Reopen whenever one of sigs arrives, SIGHUP if
+// none are given. Reopen errors go to onErr, which may be nil.
+// Call stop to stop listening.
+func (w *Writer) ReopenOnSignal(onErr func(error), sigs ...os.Signal) (stop func()) {
+	if len(sigs) == 0 {
+		sigs = []os.Signal{syscall.SIGHUP}
+	}
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, sigs...)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-c:
+				if err := w.Reopen(); err != nil && onErr != nil {
+					onErr(err)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(c)
+			close(done)
+		})
+	}
+}
+
+// Close closes the file and waits for background compression and cleanup.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return ErrClosed
+	}
+	w.closed = true
+	err := w.f.Close()
+	w.mu.Unlock()
+
+	w.wg.Wait()
+	w.millMu.Lock()
+	defer w.millMu.Unlock()
+	return errors.Join(err, w.millErr)
+}
+
+// backupName 回傳備份檔名，同一毫秒換兩次檔時加上序號避免覆蓋
+func (w *Writer) backupName(t time.Time) string {
+	dir, base, ext := w.split()
+	stamp := t.UTC().Format(TimeFormat)
+	name := filepath.Join(dir, base+"-"+stamp+ext)
+	for i := 1; exists(name) || exists(name+".gz"); i++ {
+		name = filepath.Join(dir, fmt.Sprintf("%s-%s-%d%s", base, stamp, i, ext))
+	}
+	return name
+}
+
+// split 把 /var/log/app.log 拆成 /var/log、app、.log
+func (w *Writer) split() (dir, base, ext string) {
+	dir = filepath.Dir(w.path)
+	name := filepath.Base(w.path)
+	ext = filepath.Ext(name)
+	return dir, strings.TrimSuffix(name, ext), ext
+}
+
+func exists(name string) bool {
+	_, err := os.Lstat(name)
+	return err == nil
+}
+
+// mill 在背景壓縮和清理備份，呼叫前要拿著 w.mu
+func (w *Writer) mill() {
+	if !w.opts.Compress && w.opts.MaxBackups == 0 && w.opts.MaxAge == 0 {
+		return
+	}
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		w.millMu.Lock()
+		defer w.millMu.Unlock()
+		if err := w.millOnce(); err != nil {
+			w.millErr = errors.Join(w.millErr, err)
+		}
+	}()
+}
+
+// backup 是一個備份檔
+type backup struct {
+	path string
+	t    time.Time
+	seq  int // 同一個時間的第幾個備份，沒有序號是 0
+	gz   bool
+}
+
+// Backups returns the existing backups, newest first.
+func (w *Writer) Backups() ([]string, error) {
+	bs, err := w.backups()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(bs))
+	for i, b := range bs {
+		names[i] = b.path
+	}
+	return names, nil
+}
+
+func (w *Writer) backups() ([]backup, error) {
+	dir, base, ext := w.split()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var bs []backup
+	for _, e := range entries {
+		name := e.Name()
+		rest, ok := strings.CutPrefix(name, base+"-")
+		if !ok || e.IsDir() {
+			continue
+		}
+		rest, gz := strings.CutSuffix(rest, ".gz")
+		rest, ok = strings.CutSuffix(rest, ext)
+		if !ok || len(rest) < len(TimeFormat) {
+			continue
+		}
+		t, err := time.Parse(TimeFormat, rest[:len(TimeFormat)])
+		if err != nil {
+			continue
+		}
+		// 時間後面可能有 -1 這種序號
+		var seq int
+		if s := rest[len(TimeFormat):]; s != "" {
+			if n, err := fmt.Sscanf(s, "-%d", &seq); n != 1 || err != nil {
+				continue
+			}
+		}
+		bs = append(bs, backup{path: filepath.Join(dir, name), t: t, seq: seq, gz: gz})
+	}
+	slices.SortFunc(bs, func(a, b backup) int {
+		if c := b.t.Compare(a.t); c != 0 {
+			return c
+		}
+		return b.seq - a.seq
+	})
+	return bs, nil
+}
+
+// millOnce 刪掉太多或太舊的備份，再壓縮剩下還沒壓縮的
+func (w *Writer) millOnce() error {
+	bs, err := w.backups()
+	if err != nil {
+		return err
+	}
+	var errs []error
+	cutoff := w.opts.Now().Add(-w.opts.MaxAge)
+	var keep []backup
+	for i, b := range bs {
+		tooMany := w.opts.MaxBackups > 0 && i >= w.opts.MaxBackups
+		tooOld := w.opts.MaxAge > 0 && b.t.Before(cutoff)
+		if tooMany || tooOld {
+			if err := os.Remove(b.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		keep = append(keep, b)
+	}
+	if w.opts.Compress {
+		for _, b := range keep {
+			if !b.gz {
+				if err := compress(b.path); err != nil {
+					errs = append(errs, err)
+				}
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// compress 把 name 壓縮成 name.gz 再刪掉 name；先寫暫存檔，壓到一半失敗不會留下壞的 .gz
+func compress(name string) (err error) {
+	src, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	fi, err := src.Stat()
+	if err != nil {
+		return err
+	}
+
+	tmp := name + ".gz.tmp"
+	dst, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, fi.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			os.Remove(tmp)
+		}
+	}()
+
+	zw := gzip.NewWriter(dst)
+	zw.Name = filepath.Base(name)
+	zw.ModTime = fi.ModTime()
+	_, err = io.Copy(zw, src)
+	err = errors.Join(err, zw.Close(), dst.Close())
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, name+".gz"); err != nil {
+		return err
+	}
+	src.Close()
+	return os.Remove(name)
+}
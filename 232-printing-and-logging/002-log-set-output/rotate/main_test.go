@@ -0,0 +1,366 @@
+package rotate
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// clock 是測試用的時鐘，每次呼叫 Now 都會往前走 1 秒，避免備份檔名重複
+type clock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+func newClock() *clock {
+	return &clock{t: time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC)}
+}
+
+func (c *clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.t = c.t.Add(time.Second)
+	return c.t
+}
+
+func (c *clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.t = c.t.Add(d)
+}
+
+func readFile(t *testing.T, name string) string {
+	t.Helper()
+	b, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.HasSuffix(name, ".gz") {
+		zr, err := gzip.NewReader(bytes.NewReader(b))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b, err = io.ReadAll(zr); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return string(b)
+}
+
+func TestRotateBySize(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "log.txt")
+	w, err := Open(name, Options{MaxSize: 10, Now: newClock().Now})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"aaaa\n", "bbbb\n", "cccc\n", "this line is longer than MaxSize\n"} {
+		if _, err := io.WriteString(w, s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	bs, err := w.Backups()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 備份由新到舊
+	want := []string{"cccc\n", "aaaa\nbbbb\n"}
+	if len(bs) != len(want) {
+		t.Fatal("Expected", len(want), "backups Got", bs)
+	}
+	for i, b := range bs {
+		if x := readFile(t, b); x != want[i] {
+			t.Error(b, "Expected", want[i], "Got", x)
+		}
+	}
+	if x := readFile(t, name); x != "this line is longer than MaxSize\n" {
+		t.Error("Expected the long line in the current file Got", x)
+	}
+	if !strings.HasPrefix(filepath.Base(bs[1]), "log-20250102T150") || !strings.HasSuffix(bs[1], ".txt") {
+		t.Error("unexpected backup name", bs[1])
+	}
+}
+
+func TestRotateByTime(t *testing.T) {
+	dir := t.TempDir()
+	c := newClock()
+	w, err := Open(filepath.Join(dir, "log.txt"), Options{RotateEvery: time.Hour, Now: c.Now})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	io.WriteString(w, "one\n")
+	io.WriteString(w, "two\n")
+	if bs, _ := w.Backups(); len(bs) != 0 {
+		t.Error("Expected no backups yet Got", bs)
+	}
+	c.Advance(time.Hour)
+	io.WriteString(w, "three\n")
+	bs, _ := w.Backups()
+	if len(bs) != 1 || readFile(t, bs[0]) != "one\ntwo\n" {
+		t.Error("Expected one backup with one and two Got", bs)
+	}
+}
+
+func TestMaxBackupsAndCompress(t *testing.T) {
+	dir := t.TempDir()
+	w, err := Open(filepath.Join(dir, "app.log"), Options{MaxBackups: 2, Compress: true, Now: newClock().Now})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range 5 {
+		fmt.Fprintf(w, "line %d\n", i)
+		if err := w.Rotate(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Close 會等背景的壓縮和清理做完
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	bs, _ := w.Backups()
+	want := []string{"line 4\n", "line 3\n"}
+	if len(bs) != len(want) {
+		t.Fatal("Expected", len(want), "backups Got", bs)
+	}
+	for i, b := range bs {
+		if !strings.HasSuffix(b, ".log.gz") {
+			t.Error("Expected a .gz backup Got", b)
+		}
+		if x := readFile(t, b); x != want[i] {
+			t.Error(b, "Expected", want[i], "Got", x)
+		}
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 3 {
+		t.Error("Expected app.log and 2 backups Got", entries)
+	}
+}
+
+func TestMaxAge(t *testing.T) {
+	dir := t.TempDir()
+	c := newClock()
+	w, err := Open(filepath.Join(dir, "log.txt"), Options{MaxAge: 24 * time.Hour, Now: c.Now})
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.WriteString(w, "old\n")
+	w.Rotate()
+	c.Advance(48 * time.Hour)
+	io.WriteString(w, "new\n")
+	w.Rotate()
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	bs, _ := w.Backups()
+	if len(bs) != 1 || readFile(t, bs[0]) != "new\n" {
+		t.Error("Expected only the new backup Got", bs)
+	}
+}
+
+// 同一毫秒換兩次檔，第二個備份要加序號，不能覆蓋第一個
+func TestSameTimestamp(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+	w, err := Open(filepath.Join(dir, "log.txt"), Options{Now: func() time.Time { return now }})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"a", "b", "c"} {
+		io.WriteString(w, s)
+		w.Rotate()
+	}
+	w.Close()
+	bs, _ := w.Backups()
+	if len(bs) != 3 {
+		t.Fatal("Expected 3 backups Got", bs)
+	}
+	got := readFile(t, bs[0]) + readFile(t, bs[1]) + readFile(t, bs[2])
+	if got != "cba" {
+		t.Error("Expected", "cba", "Got", got, bs)
+	}
+}
+
+func TestConcurrentWriters(t *testing.T) {
+	dir := t.TempDir()
+	w, err := Open(filepath.Join(dir, "log.txt"), Options{MaxSize: 1000, Compress: true, MaxBackups: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := log.New(w, "", 0)
+
+	const goroutines, lines = 8, 200
+	var wg sync.WaitGroup
+	for g := range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range lines {
+				l.Printf("goroutine %d line %03d", g, i)
+			}
+		}()
+	}
+	wg.Wait()
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 每一行都要完整地出現在某個檔案裡，不能被切開或弄丟
+	files, _ := w.Backups()
+	files = append(files, filepath.Join(dir, "log.txt"))
+	count := 0
+	for _, f := range files {
+		for _, line := range strings.Split(strings.TrimSuffix(readFile(t, f), "\n"), "\n") {
+			if line == "" {
+				continue
+			}
+			var g, i int
+			if _, err := fmt.Sscanf(line, "goroutine %d line %d", &g, &i); err != nil {
+				t.Error("broken line", line)
+			}
+			count++
+		}
+	}
+	if count != goroutines*lines {
+		t.Error("Expected", goroutines*lines, "lines Got", count)
+	}
+}
+
+// 模擬 logrotate：檔案被搬走後 Reopen，新的 log 寫到同名的新檔
+func TestReopen(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "log.txt")
+	w, err := Open(name, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	io.WriteString(w, "before\n")
+	if err := os.Rename(name, name+".1"); err != nil {
+		t.Fatal(err)
+	}
+	// 還沒 Reopen 之前，寫入會跟著被搬走的檔案
+	io.WriteString(w, "moved\n")
+	if err := w.Reopen(); err != nil {
+		t.Fatal(err)
+	}
+	io.WriteString(w, "after\n")
+
+	if x := readFile(t, name+".1"); x != "before\nmoved\n" {
+		t.Error("Expected before and moved Got", x)
+	}
+	if x := readFile(t, name); x != "after\n" {
+		t.Error("Expected after Got", x)
+	}
+}
+
+func TestRotateCloseError(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "log.txt")
+	w, err := Open(name, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	io.WriteString(w, "before\n")
+	// 讓 rotate 裡的 Close 失敗
+	w.f.Close()
+	if err := w.Rotate(); err == nil {
+		t.Error("Expected the close error")
+	}
+	// 之後的寫入要接在原本的檔案後面，不能一直失敗
+	if _, err := io.WriteString(w, "after\n"); err != nil {
+		t.Error("Expected nil Got", err)
+	}
+	if x := readFile(t, name); x != "before\nafter\n" {
+		t.Error("Expected before and after Got", x)
+	}
+}
+
+func TestReopenOnSignal(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("no SIGHUP on windows")
+	}
+	dir := t.TempDir()
+	name := filepath.Join(dir, "log.txt")
+	w, err := Open(name, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	stop := w.ReopenOnSignal(func(err error) { t.Error(err) })
+	defer stop()
+
+	os.Rename(name, name+".1")
+	p, _ := os.FindProcess(os.Getpid())
+	if err := p.Signal(syscall.SIGHUP); err != nil {
+		t.Fatal(err)
+	}
+	// 訊號是非同步的，等新檔出現
+	deadline := time.Now().Add(5 * time.Second)
+	for !exists(name) {
+		if time.Now().After(deadline) {
+			t.Fatal("file was not reopened after SIGHUP")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	io.WriteString(w, "after\n")
+	if x := readFile(t, name); x != "after\n" {
+		t.Error("Expected after Got", x)
+	}
+}
+
+func TestClosed(t *testing.T) {
+	w, err := Open(filepath.Join(t.TempDir(), "log.txt"), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+	if _, err := w.Write([]byte("x")); err != ErrClosed {
+		t.Error("Expected", ErrClosed, "Got", err)
+	}
+	for _, f := range []func() error{w.Rotate, w.Reopen, w.Close} {
+		if err := f(); err != ErrClosed {
+			t.Error("Expected", ErrClosed, "Got", err)
+		}
+	}
+}
+
+func ExampleOpen() {
+	dir, _ := os.MkdirTemp("", "rotate")
+	defer os.RemoveAll(dir)
+
+	w, err := Open(filepath.Join(dir, "log.txt"), Options{MaxSize: 1 << 20, MaxBackups: 3, Compress: true})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer w.Close()
+
+	l := log.New(w, "", 0)
+	l.Println("err happened:", os.ErrNotExist)
+
+	b, _ := os.ReadFile(filepath.Join(dir, "log.txt"))
+	fmt.Print(string(b))
+	// Output:
+	// err happened: file does not exist
+}
@@ -1,9 +1,12 @@
 package main
 
 import (
-	"fmt" // 提供格式化輸出功能
-	"log" // 提供日誌與致命錯誤輸出
-	"os"  // 提供作業系統功能（如檔案操作）
+	"fmt"      // 提供格式化輸出功能
+	"log"      // 提供日誌與致命錯誤輸出
+	"log/slog" // 結構化、有等級的日誌
+	"os"       // 提供作業系統功能（如檔案操作）
+
+	"github.com/andyrestart9/animalPackage/232-printing-and-logging/logger"
 )
 
 func main() {
@@ -11,11 +14,23 @@ func main() {
 	// 若程式透過 os.Exit 或 log.Fatalln (內部呼叫 os.Exit) 結束，defer 不會執行
 	defer foo()
 
+	// 改用 logger 的話，用 OnExit 註冊的清理函式在 logger.Fatal 結束程式前一定會執行
+	// LOG_FORMAT=json 可以改成 JSON 輸出，LOG_LEVEL=debug 可以調整等級
+	l, err := logger.FromEnv(nil)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	slog.SetDefault(l)
+	logger.OnExit(foo)
+
 	// 嘗試以唯讀模式開啟 "xx.txt"，只關心可能的錯誤
-	_, err := os.Open("xx.txt")
+	_, err = os.Open("xx.txt")
 	if err != nil {
 		// 若開檔失敗，log.Fatalln 會先輸出錯誤訊息，然後呼叫 os.Exit(1) 直接終止程式
-		log.Fatalln("err happened:", err)
+		// log.Fatalln("err happened:", err)
+
+		// logger.Fatal 一樣會結束程式，但會先執行 OnExit 註冊的 foo()
+		logger.Fatal("err happened", "err", err)
 	}
 
 	// 如果成功開檔且 main 正常結束，defer foo() 才會在此後被執行
@@ -2,8 +2,9 @@ package main
 
 import (
 	"fmt" // 提供格式化輸出功能
-	"log" // 提供日誌功能，包含 Panicln
 	"os"  // 提供作業系統功能（如檔案操作）
+
+	"github.com/andyrestart9/animalPackage/232-printing-and-logging/logger"
 )
 
 func main() {
@@ -15,7 +16,10 @@ func main() {
 	if err != nil {
 		// 若開檔失敗，log.Panicln 會先輸出錯誤訊息，接著呼叫 panic()
 		// panic 會觸發堆疊展開，並依序執行所有註冊的 defer
-		log.Panicln("err happened:", err)
+		// log.Panicln("err happened:", err)
+
+		// logger.Panic 用 slog.Default 記一筆 ERROR，再呼叫 panic()
+		logger.Panic("err happened", "err", err)
 	}
 
 	// 若沒有發生 panic，main 結束前也會執行 defer foo()
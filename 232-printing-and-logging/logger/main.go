@@ -0,0 +1,309 @@
+// Package logger builds log/slog loggers with per-package levels and a
+// Fatal that runs cleanup hooks before exiting.
+//
+// 232 的幾課用的是 log.Println、log.Fatalln、log.Panicln，沒有等級也沒有欄位，
+// 而且 003-log-fatalln 示範了 log.Fatalln 會直接 os.Exit，defer 的 foo() 不會執行。
+// 這個套件：
+//   - 用 LOG_FORMAT 選 text 或 json
+//   - 用 LOG_LEVEL 設定預設等級和個別套件的等級，例如 LOG_LEVEL=info,dog=debug
+//   - OnExit 註冊清理函式，Fatal 和 Exit 會先執行它們再結束程式
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"os"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Environment variables read by FromEnv.
+const (
+	EnvLevel  = "LOG_LEVEL"
+	EnvFormat = "LOG_FORMAT"
+)
+
+// LevelFatal is the level Fatal logs at, above slog.LevelError.
+const LevelFatal = slog.Level(12)
+
+// ErrBadLevel is returned for an unparsable LOG_LEVEL entry.
+var ErrBadLevel = errors.New("logger: bad level")
+
+// Options configures New. The zero value logs text at Info to stderr.
+type Options struct {
+	Format string    // "text" 或 "json"，空字串是 text
+	Writer io.Writer // nil 是 os.Stderr
+	Level  slog.Level
+	// Levels 覆寫個別套件的等級，key 是 import 路徑或它最後幾段，例如 "dog" 或 "part-1/dog"
+	Levels    map[string]slog.Level
+	AddSource bool
+}
+
+// New returns a logger configured by opts.
+func New(opts Options) (*slog.Logger, error) {
+	w := opts.Writer
+	if w == nil {
+		w = os.Stderr
+	}
+	// 給 Handler 的等級要是所有設定裡最低的，真正的過濾在 levelHandler 做
+	lowest := opts.Level
+	for _, l := range opts.Levels {
+		lowest = min(lowest, l)
+	}
+	hopts := &slog.HandlerOptions{Level: lowest, AddSource: opts.AddSource, ReplaceAttr: replaceLevel}
+
+	var h slog.Handler
+	switch strings.ToLower(opts.Format) {
+	case "", "text":
+		h = slog.NewTextHandler(w, hopts)
+	case "json":
+		h = slog.NewJSONHandler(w, hopts)
+	default:
+		return nil, fmt.Errorf("logger: unknown format %q", opts.Format)
+	}
+	return slog.New(&levelHandler{inner: h, level: opts.Level, levels: opts.Levels, lowest: lowest}), nil
+}
+
+// FromEnv returns a logger configured by LOG_FORMAT and LOG_LEVEL,
+// writing to w (nil for os.Stderr).
+func FromEnv(w io.Writer) (*slog.Logger, error) {
+	level, levels, err := ParseLevels(os.Getenv(EnvLevel))
+	if err != nil {
+		return nil, err
+	}
+	return New(Options{Format: os.Getenv(EnvFormat), Writer: w, Level: level, Levels: levels})
+}
+
+// ParseLevels parses a spec such as "info,dog=debug,geometry=warn".
+// An entry without "=" sets the default level; the empty spec is Info.
+func ParseLevels(spec string) (slog.Level, map[string]slog.Level, error) {
+	def := slog.LevelInfo
+	levels := map[string]slog.Level{}
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		pkg, lvl, found := strings.Cut(part, "=")
+		if !found {
+			pkg, lvl = "", part
+		}
+		l, err := parseLevel(lvl)
+		if err != nil {
+			return 0, nil, fmt.Errorf("%w: %q", ErrBadLevel, part)
+		}
+		if found {
+			levels[strings.TrimSpace(pkg)] = l
+		} else {
+			def = l
+		}
+	}
+	return def, levels, nil
+}
+
+func parseLevel(s string) (slog.Level, error) {
+	if strings.EqualFold(strings.TrimSpace(s), "fatal") {
+		return LevelFatal, nil
+	}
+	var l slog.Level
+	err := l.UnmarshalText([]byte(strings.TrimSpace(s)))
+	return l, err
+}
+
+// replaceLevel 讓 LevelFatal 印成 FATAL 而不是 ERROR+4
+func replaceLevel(groups []string, a slog.Attr) slog.Attr {
+	if a.Key == slog.LevelKey && len(groups) == 0 {
+		if l, ok := a.Value.Any().(slog.Level); ok && l == LevelFatal {
+			a.Value = slog.StringValue("FATAL")
+		}
+	}
+	return a
+}
+
+// levelHandler 依記錄是從哪個套件送出的來決定等級
+type levelHandler struct {
+	inner  slog.Handler
+	level  slog.Level
+	levels map[string]slog.Level
+	lowest slog.Level
+}
+
+func (h *levelHandler) Enabled(ctx context.Context, l slog.Level) bool {
+	// 這時候還不知道是哪個套件，只要有任何設定會收就先收下
+	return l >= h.lowest && h.inner.Enabled(ctx, l)
+}
+
+func (h *levelHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level < h.levelFor(r.PC) {
+		return nil
+	}
+	return h.inner.Handle(ctx, r)
+}
+
+func (h *levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+	h2.inner = h.inner.WithAttrs(attrs)
+	return &h2
+}
+
+func (h *levelHandler) WithGroup(name string) slog.Handler {
+	h2 := *h
+	h2.inner = h.inner.WithGroup(name)
+	return &h2
+}
+
+// levelFor 回傳 pc 所在套件的等級，最長（最具體）的 key 優先
+func (h *levelHandler) levelFor(pc uintptr) slog.Level {
+	if len(h.levels) == 0 || pc == 0 {
+		return h.level
+	}
+	pkg := pkgPath(pc)
+	level, best := h.level, -1
+	for key, l := range h.levels {
+		if (pkg == key || strings.HasSuffix(pkg, "/"+key)) && len(key) > best {
+			level, best = l, len(key)
+		}
+	}
+	return level
+}
+
+var pkgCache sync.Map // uintptr -> string
+
+// pkgPath 從 pc 找出函式所屬的 import 路徑
+func pkgPath(pc uintptr) string {
+	if p, ok := pkgCache.Load(pc); ok {
+		return p.(string)
+	}
+	fs := runtime.CallersFrames([]uintptr{pc})
+	f, _ := fs.Next()
+	p := funcPkg(f.Function)
+	pkgCache.Store(pc, p)
+	return p
+}
+
+// funcPkg 把 "example.com/a/dog.(*Dog).Walk" 變成 "example.com/a/dog"
+//
+// 編譯器會把 import 路徑最後一段裡的 . 跳脫成 %2e，
+// 例如 gopkg.in/yaml.v3 的函式叫 "gopkg.in/yaml%2ev3.Unmarshal"，
+// 所以最後一段的第一個 . 一定是套件和函式名稱的分界，切完再還原跳脫
+func funcPkg(fn string) string {
+	slash := strings.LastIndex(fn, "/")
+	if dot := strings.Index(fn[slash+1:], "."); dot >= 0 {
+		fn = fn[:slash+1+dot]
+	}
+	if p, err := url.PathUnescape(fn); err == nil {
+		return p
+	}
+	return fn
+}
+
+var (
+	hooksMu sync.Mutex
+	hooks   []*func()
+	// exit 讓測試可以換掉 os.Exit
+	exit   = osExit
+	osExit = os.Exit
+	// hookOut 是清理函式 panic 時回報的地方
+	hookOut io.Writer = os.Stderr
+)
+
+// OnExit registers f to run before Fatal or Exit terminates the program.
+// Hooks run in reverse order of registration, like defer. remove
+// unregisters f.
+func OnExit(f func()) (remove func()) {
+	p := &f
+	hooksMu.Lock()
+	hooks = append(hooks, p)
+	hooksMu.Unlock()
+	return func() {
+		hooksMu.Lock()
+		defer hooksMu.Unlock()
+		hooks = slices.DeleteFunc(hooks, func(h *func()) bool { return h == p })
+	}
+}
+
+// RunHooks runs and clears the registered hooks. A panicking hook does
+// not stop the others; its panic is reported on stderr.
+func RunHooks() {
+	hooksMu.Lock()
+	hs := hooks
+	hooks = nil
+	hooksMu.Unlock()
+	for i := len(hs) - 1; i >= 0; i-- {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					fmt.Fprintf(hookOut, "logger: exit hook panicked: %v\n", r)
+				}
+			}()
+			(*hs[i])()
+		}()
+	}
+}
+
+// Exit runs the hooks and exits with code.
+func Exit(code int) {
+	RunHooks()
+	exit(code)
+}
+
+// Fatal logs msg at LevelFatal with slog.Default, runs the hooks and
+// exits with status 1. 取代 log.Fatalln：defer 一樣不會執行，但 OnExit 註冊的會。
+func Fatal(msg string, args ...any) {
+	logAt(LevelFatal, msg, args...)
+	Exit(1)
+}
+
+// Panic logs msg at slog.LevelError with slog.Default and panics with an
+// error whose message is msg followed by args as key=value pairs, and
+// which wraps every error in args.
+// 取代 log.Panicln：panic 會展開堆疊，defer 都會執行，recover 拿到的值也保有原因。
+func Panic(msg string, args ...any) {
+	logAt(slog.LevelError, msg, args...)
+	panic(newPanicError(msg, args))
+}
+
+// panicError 是 Panic 的 panic 值
+type panicError struct {
+	msg  string
+	errs []error
+}
+
+func newPanicError(msg string, args []any) *panicError {
+	e := &panicError{msg: msg}
+	// 借用 slog.Record 把 args 整理成 Attr，格式跟 log 一致
+	r := slog.NewRecord(time.Time{}, slog.LevelError, msg, 0)
+	r.Add(args...)
+	r.Attrs(func(a slog.Attr) bool {
+		e.msg += " " + a.String()
+		if err, ok := a.Value.Any().(error); ok {
+			e.errs = append(e.errs, err)
+		}
+		return true
+	})
+	return e
+}
+
+func (e *panicError) Error() string   { return e.msg }
+func (e *panicError) Unwrap() []error { return e.errs }
+
+// logAt 用呼叫者的 pc 記錄，這樣套件等級和 AddSource 才會指到呼叫 Fatal 的地方
+func logAt(level slog.Level, msg string, args ...any) {
+	l := slog.Default()
+	ctx := context.Background()
+	if !l.Enabled(ctx, level) {
+		return
+	}
+	var pcs [1]uintptr
+	runtime.Callers(3, pcs[:]) // runtime.Callers、logAt、Fatal/Panic
+	r := slog.NewRecord(time.Now(), level, msg, pcs[0])
+	r.Add(args...)
+	l.Handler().Handle(ctx, r)
+}
@@ -0,0 +1,192 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"maps"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestParseLevels(t *testing.T) {
+	type test struct {
+		spec   string
+		def    slog.Level
+		levels map[string]slog.Level
+	}
+	tests := []test{
+		{"", slog.LevelInfo, map[string]slog.Level{}},
+		{"debug", slog.LevelDebug, map[string]slog.Level{}},
+		{"WARN, dog=debug ,geometry=error", slog.LevelWarn, map[string]slog.Level{"dog": slog.LevelDebug, "geometry": slog.LevelError}},
+		{"dog=info+2,fatal", LevelFatal, map[string]slog.Level{"dog": slog.LevelInfo + 2}},
+	}
+	for _, v := range tests {
+		def, levels, err := ParseLevels(v.spec)
+		if err != nil || def != v.def || !maps.Equal(levels, v.levels) {
+			t.Error("Spec", v.spec, "Expected", v.def, v.levels, "Got", def, levels, err)
+		}
+	}
+
+	for _, spec := range []string{"loud", "dog=", "dog=verbose"} {
+		if _, _, err := ParseLevels(spec); !errors.Is(err, ErrBadLevel) {
+			t.Error("Spec", spec, "Expected", ErrBadLevel, "Got", err)
+		}
+	}
+}
+
+func TestFuncPkg(t *testing.T) {
+	tests := map[string]string{
+		"github.com/andyrestart9/animalPackage/174-method-sets-part-1/dog.(*Dog).Walk": "github.com/andyrestart9/animalPackage/174-method-sets-part-1/dog",
+		"github.com/a/b%2ev2.F":                "github.com/a/b.v2",
+		"gopkg.in/yaml%2ev3.(*Decoder).Decode": "gopkg.in/yaml.v3",
+		"main.main":                            "main",
+		"main.main.func1":                      "main",
+		"log/slog.(*Logger).Info":              "log/slog",
+	}
+	for fn, want := range tests {
+		if got := funcPkg(fn); got != want {
+			t.Error(fn, "Expected", want, "Got", got)
+		}
+	}
+}
+
+func TestPackageLevels(t *testing.T) {
+	const self = "232-printing-and-logging/logger"
+	type test struct {
+		level  slog.Level
+		levels map[string]slog.Level
+		debug  bool // 這個測試套件的 Debug 會不會印出來
+		info   bool
+	}
+	tests := []test{
+		{slog.LevelInfo, nil, false, true},
+		{slog.LevelWarn, map[string]slog.Level{"logger": slog.LevelDebug}, true, true},
+		{slog.LevelWarn, map[string]slog.Level{"dog": slog.LevelDebug}, false, false},
+		// 比較長的 key 優先
+		{slog.LevelDebug, map[string]slog.Level{"logger": slog.LevelDebug, self: slog.LevelError}, false, false},
+	}
+	for _, v := range tests {
+		var buf bytes.Buffer
+		l, err := New(Options{Writer: &buf, Level: v.level, Levels: v.levels})
+		if err != nil {
+			t.Fatal(err)
+		}
+		l.Debug("debug line")
+		l.With("k", "v").WithGroup("g").Info("info line")
+		out := buf.String()
+		if strings.Contains(out, "debug line") != v.debug || strings.Contains(out, "info line") != v.info {
+			t.Error("Levels", v.level, v.levels, "Expected debug", v.debug, "info", v.info, "Got", out)
+		}
+	}
+}
+
+func TestJSON(t *testing.T) {
+	var buf bytes.Buffer
+	l, err := New(Options{Format: "json", Writer: &buf})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Log(context.Background(), LevelFatal, "boom", "file", "xx.txt")
+	var m map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+		t.Fatal(err, buf.String())
+	}
+	if m["level"] != "FATAL" || m["msg"] != "boom" || m["file"] != "xx.txt" {
+		t.Error("Expected FATAL boom xx.txt Got", m)
+	}
+
+	if _, err := New(Options{Format: "xml"}); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv(EnvFormat, "json")
+	t.Setenv(EnvLevel, "error,logger=debug")
+	var buf bytes.Buffer
+	l, err := FromEnv(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Debug("hello")
+	if !strings.HasPrefix(buf.String(), "{") || !strings.Contains(buf.String(), `"msg":"hello"`) {
+		t.Error("Expected a JSON debug line Got", buf.String())
+	}
+
+	t.Setenv(EnvLevel, "nope")
+	if _, err := FromEnv(&buf); !errors.Is(err, ErrBadLevel) {
+		t.Error("Expected", ErrBadLevel, "Got", err)
+	}
+}
+
+// 換掉 os.Exit 和 slog.Default，確認 Fatal 先記錄、再依反序執行清理函式、最後才結束
+func TestFatal(t *testing.T) {
+	var buf bytes.Buffer
+	l, _ := New(Options{Writer: &buf})
+	old := slog.Default()
+	slog.SetDefault(l)
+	defer slog.SetDefault(old)
+
+	var calls []string
+	exit = func(code int) { calls = append(calls, "exit") }
+	defer func() { exit = osExit }()
+	var hookBuf bytes.Buffer
+	hookOut = &hookBuf
+	defer func() { hookOut = os.Stderr }()
+
+	OnExit(func() { calls = append(calls, "first") })
+	remove := OnExit(func() { calls = append(calls, "removed") })
+	OnExit(func() { panic("hooks keep running") })
+	OnExit(func() { calls = append(calls, "last") })
+	remove()
+
+	Fatal("err happened", "err", "open xx.txt: no such file or directory")
+
+	want := []string{"last", "first", "exit"}
+	if strings.Join(calls, ",") != strings.Join(want, ",") {
+		t.Error("Expected", want, "Got", calls)
+	}
+	if x := hookBuf.String(); !strings.Contains(x, "hooks keep running") {
+		t.Error("Expected the hook panic on stderr Got", x)
+	}
+	if out := buf.String(); !strings.Contains(out, "level=FATAL") || !strings.Contains(out, `msg="err happened"`) {
+		t.Error("Expected a FATAL line Got", out)
+	}
+
+	// 清理函式只執行一次
+	calls = nil
+	Exit(0)
+	if strings.Join(calls, ",") != "exit" {
+		t.Error("Expected only exit Got", calls)
+	}
+}
+
+func TestPanic(t *testing.T) {
+	var buf bytes.Buffer
+	l, _ := New(Options{Writer: &buf, AddSource: true})
+	old := slog.Default()
+	slog.SetDefault(l)
+	defer slog.SetDefault(old)
+
+	errNotExist := errors.New("no such file or directory")
+	defer func() {
+		r := recover()
+		err, ok := r.(error)
+		if !ok || err.Error() != "err happened file=xx.txt err=no such file or directory" {
+			t.Error("Expected panic err happened with args Got", r)
+		}
+		// recover 的人還是可以用 errors.Is 找到原因
+		if !errors.Is(err, errNotExist) {
+			t.Error("Expected the panic to wrap", errNotExist, "Got", r)
+		}
+		// AddSource 要指到呼叫 Panic 的這個檔案
+		if out := buf.String(); !strings.Contains(out, "level=ERROR") || !strings.Contains(out, "main_test.go") {
+			t.Error("Expected an ERROR line from main_test.go Got", out)
+		}
+	}()
+	Panic("err happened", "file", "xx.txt", "err", errNotExist)
+}
@@ -0,0 +1,139 @@
+// Package safe turns panics into errors.
+//
+// 233-002-recover 的 f() 用 defer + recover 接住 g 的 panic，237 的 safeAssert 也是手寫一樣的東西。
+// 這個套件把這個模式包起來：
+//   - Safely 執行一個函式，panic 變成帶有 panic 值和堆疊的 *PanicError
+//   - Go 開一個 goroutine，panic 或回傳的錯誤交給 callback，不會讓整個程式崩潰
+//   - Middleware 把 http.Handler 裡的 panic 變成 500 回應
+//
+// recover 只能接住同一個 goroutine 的 panic，所以 goroutine 一定要用 Go 開才有保護。
+package safe
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"runtime/debug"
+)
+
+// PanicError is a recovered panic.
+type PanicError struct {
+	Value any    // panic 傳出的值
+	Stack []byte // panic 發生時的 goroutine 堆疊，跟 debug.Stack 的格式一樣
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns the panic value if it is an error, so errors.Is and
+// errors.As see through a panic(err).
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// Safely calls f and returns its error, or a *PanicError if f panics.
+func Safely(f func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{Value: r, Stack: debug.Stack()}
+		}
+	}()
+	return f()
+}
+
+// Go runs f in a new goroutine under Safely. If f returns an error or
+// panics, report is called with it from that goroutine. report may be
+// nil to drop errors. The returned channel is closed when f is done.
+func Go(f func() error, report func(error)) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if err := Safely(f); err != nil && report != nil {
+			report(err)
+		}
+	}()
+	return done
+}
+
+// Middleware returns a handler that calls next and turns a panic into a
+// 500 Internal Server Error. onPanic, if not nil, is called with the
+// request and the recovered panic, e.g. to log it.
+//
+// http.ErrAbortHandler 是 net/http 用來中止回應的 panic，照樣往上丟。
+// 如果 handler 已經開始寫回應，狀態碼改不了，只會呼叫 onPanic。
+//
+// The ResponseWriter passed to next always implements http.Flusher,
+// http.Hijacker and io.ReaderFrom, forwarding to the original writer.
+// Hijack returns an error wrapping http.ErrNotSupported when the original
+// writer cannot be hijacked.
+func Middleware(next http.Handler, onPanic func(*http.Request, *PanicError)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rw := &recordingWriter{ResponseWriter: w}
+		err := Safely(func() error {
+			next.ServeHTTP(rw, r)
+			return nil
+		})
+		var pe *PanicError
+		if !errors.As(err, &pe) {
+			return
+		}
+		if pe.Value == http.ErrAbortHandler {
+			panic(http.ErrAbortHandler)
+		}
+		if onPanic != nil {
+			onPanic(r, pe)
+		}
+		if !rw.wrote {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+	})
+}
+
+// recordingWriter 記錄 handler 是不是已經送出標頭
+type recordingWriter struct {
+	http.ResponseWriter
+	wrote bool
+}
+
+func (w *recordingWriter) WriteHeader(code int) {
+	w.wrote = true
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *recordingWriter) Write(b []byte) (int, error) {
+	w.wrote = true
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w *recordingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// 下面三個方法讓對 http.Flusher、http.Hijacker、io.ReaderFrom 做型別斷言的 handler 照常運作；
+// 只嵌入 http.ResponseWriter 的話，這些介面會被藏起來
+
+func (w *recordingWriter) Flush() {
+	w.wrote = true
+	http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+func (w *recordingWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := http.NewResponseController(w.ResponseWriter).Hijack()
+	if err == nil {
+		// 連線已經交給 handler，不能再寫 500
+		w.wrote = true
+	}
+	return conn, rw, err
+}
+
+func (w *recordingWriter) ReadFrom(r io.Reader) (int64, error) {
+	w.wrote = true
+	// io.Copy 會用原本 writer 的 ReadFrom（如果有的話）
+	return io.Copy(w.ResponseWriter, r)
+}
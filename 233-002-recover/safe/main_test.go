@@ -0,0 +1,184 @@
+package safe
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// g 跟 233-002-recover 的 g 一樣，遞迴到 i > 3 時 panic
+func g(i int) {
+	if i > 3 {
+		panic(fmt.Sprintf("%v", i))
+	}
+	g(i + 1)
+}
+
+func TestSafely(t *testing.T) {
+	if err := Safely(func() error { return nil }); err != nil {
+		t.Error("Expected nil Got", err)
+	}
+	boom := errors.New("boom")
+	if err := Safely(func() error { return boom }); err != boom {
+		t.Error("Expected", boom, "Got", err)
+	}
+
+	err := Safely(func() error { g(0); return nil })
+	var pe *PanicError
+	if !errors.As(err, &pe) {
+		t.Fatal("Expected *PanicError Got", err)
+	}
+	if pe.Value != "4" || err.Error() != "panic: 4" {
+		t.Error("Expected", "panic: 4", "Got", err)
+	}
+	// 堆疊要看得到 panic 發生的地方
+	if !strings.Contains(string(pe.Stack), "safe.g(") {
+		t.Error("Expected g in the stack Got", string(pe.Stack))
+	}
+}
+
+func TestPanicErrorUnwrap(t *testing.T) {
+	err := Safely(func() error { panic(fmt.Errorf("open: %w", fs.ErrNotExist)) })
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Error("Expected fs.ErrNotExist in", err)
+	}
+
+	// 型別斷言失敗的 panic 是 *runtime.TypeAssertionError，也是 error
+	err = Safely(func() error {
+		var v any = "hello"
+		_ = v.(int)
+		return nil
+	})
+	var re interface{ RuntimeError() }
+	if !errors.As(err, &re) {
+		t.Error("Expected a runtime.Error Got", err)
+	}
+
+	if (&PanicError{Value: 1}).Unwrap() != nil {
+		t.Error("Expected nil Unwrap for a non-error value")
+	}
+}
+
+func TestGo(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		errs []error
+	)
+	report := func(err error) {
+		mu.Lock()
+		errs = append(errs, err)
+		mu.Unlock()
+	}
+	boom := errors.New("boom")
+	<-Go(func() error { g(0); return nil }, report)
+	<-Go(func() error { return boom }, report)
+	<-Go(func() error { return nil }, report)
+	<-Go(func() error { panic("dropped") }, nil)
+
+	if len(errs) != 2 {
+		t.Fatal("Expected 2 errors Got", errs)
+	}
+	var pe *PanicError
+	if !errors.As(errs[0], &pe) || pe.Value != "4" {
+		t.Error("Expected panic 4 Got", errs[0])
+	}
+	if errs[1] != boom {
+		t.Error("Expected", boom, "Got", errs[1])
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	var got []string
+	onPanic := func(r *http.Request, pe *PanicError) {
+		got = append(got, r.URL.Path+" "+pe.Error())
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, r *http.Request) { fmt.Fprint(w, "ok") })
+	mux.HandleFunc("/panic", func(w http.ResponseWriter, r *http.Request) { g(0) })
+	mux.HandleFunc("/late", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		panic("after header")
+	})
+	h := Middleware(mux, onPanic)
+
+	type test struct {
+		path string
+		code int
+		body string
+	}
+	tests := []test{
+		{"/ok", 200, "ok"},
+		{"/panic", 500, "Internal Server Error\n"},
+		// 已經送出標頭就改不了狀態碼
+		{"/late", 202, ""},
+	}
+	for _, v := range tests {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("GET", v.path, nil))
+		if rec.Code != v.code || rec.Body.String() != v.body {
+			t.Error(v.path, "Expected", v.code, v.body, "Got", rec.Code, rec.Body.String())
+		}
+	}
+	want := []string{"/panic panic: 4", "/late panic: after header"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Error("Expected", want, "Got", got)
+	}
+
+	// 包過的 ResponseWriter 不能藏住 Flusher、Hijacker、ReaderFrom
+	var ifaces []string
+	flushed := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if f, ok := w.(http.Flusher); ok {
+			ifaces = append(ifaces, "Flusher")
+			f.Flush()
+		}
+		if h, ok := w.(http.Hijacker); ok {
+			// ResponseRecorder 不能 hijack
+			if _, _, err := h.Hijack(); errors.Is(err, http.ErrNotSupported) {
+				ifaces = append(ifaces, "Hijacker")
+			}
+		}
+		if rf, ok := w.(io.ReaderFrom); ok {
+			ifaces = append(ifaces, "ReaderFrom")
+			rf.ReadFrom(strings.NewReader("streamed"))
+		}
+		panic("after flush")
+	}), nil)
+	rec := httptest.NewRecorder()
+	flushed.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	if x := strings.Join(ifaces, ","); x != "Flusher,Hijacker,ReaderFrom" {
+		t.Error("Expected Flusher,Hijacker,ReaderFrom Got", x)
+	}
+	// 已經 Flush 過，不能再改成 500
+	if !rec.Flushed || rec.Code != 200 || rec.Body.String() != "streamed" {
+		t.Error("Expected a flushed 200 with streamed Got", rec.Flushed, rec.Code, rec.Body.String())
+	}
+
+	// http.ErrAbortHandler 要照樣往上丟
+	abort := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}), nil)
+	func() {
+		defer func() {
+			if r := recover(); r != http.ErrAbortHandler {
+				t.Error("Expected", http.ErrAbortHandler, "Got", r)
+			}
+		}()
+		abort.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	}()
+}
+
+func ExampleSafely() {
+	err := Safely(func() error {
+		g(0)
+		return nil
+	})
+	fmt.Println("Recovered in f", err)
+	// Output:
+	// Recovered in f panic: 4
+}
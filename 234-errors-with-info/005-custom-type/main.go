@@ -1,39 +1,43 @@
 package main
 
 import (
-	"fmt" // 提供格式化輸出功能
-	"log" // 提供日誌功能，用於列印錯誤
-	"math" // 提供數學函式庫，包含 Sqrt
-)
-
-// 定義自訂錯誤類型，包含錯誤名稱與原始錯誤
-type wrongMathError struct {
-	name string // 錯誤名稱
-	err  error  // 原始錯誤
-}
+	"encoding/json" // 將錯誤轉成 JSON
+	"errors"        // 提供 errors.Is、errors.As
+	"fmt"           // 提供格式化輸出功能
+	"log"           // 提供日誌功能，用於列印錯誤
+	"math"          // 提供數學函式庫，包含 Sqrt
 
-// 為 wrongMathError 實作 Error() 方法，以符合 error 介面
-func (w wrongMathError) Error() string {
-	// 回傳結合 name 與原始 err 的格式化錯誤訊息
-	return fmt.Sprintf("a wrong math error occurred: %v %v", w.name, w.err)
-}
+	"github.com/andyrestart9/animalPackage/234-errors-with-info/richerr"
+)
 
 func main() {
 	// 呼叫 sqrt 嘗試計算 -10 的平方根，只關心錯誤
 	_, err := sqrt(-10)
 	if err != nil {
 		// 若 err 不為 nil，將錯誤寫入日誌
-		log.Println(err)
+		log.Println(err) // sqrt: domain: square root of negative number: -10
+
+		// 用錯誤代碼判斷種類，不用比對字串
+		fmt.Println(errors.Is(err, richerr.Domain)) // true
+
+		// 取出完整的錯誤，%+v 會連建立錯誤時的呼叫堆疊一起印出來
+		var e *richerr.Error
+		if errors.As(err, &e) {
+			fmt.Printf("%+v\n", e)
+		}
+
+		// 給 API 回應用的 JSON，預設不含堆疊
+		b, _ := json.Marshal(err)
+		fmt.Println(string(b))
 	}
 }
 
-// sqrt 計算輸入 f 的平方根，如果 f < 0，回傳自訂錯誤
+// sqrt 計算輸入 f 的平方根，如果 f < 0，回傳 richerr.Error
 func sqrt(f float64) (float64, error) {
 	if f < 0 {
-		// 建立一個描述性原始錯誤
-		wme := fmt.Errorf("wrong math redux: square root of negative number: %v", f)
-		// 回傳 0 及 wrongMathError 實例
-		return 0, wrongMathError{"sqrt err", wme}
+		// 原本是 wrongMathError{"sqrt err", fmt.Errorf(...)}
+		// 現在錯誤名稱放在 Op，種類放在 Code，輸入值放在 Details
+		return 0, richerr.Errorf(richerr.Domain, "sqrt", "square root of negative number: %v", f).With("input", f)
 	}
 	// 正常情況下，使用 math.Sqrt 計算並回傳結果，err 為 nil
 	return math.Sqrt(f), nil
@@ -0,0 +1,312 @@
+// Package richerr is a general form of the wrongMathError type that
+// 234-errors-with-info/005-custom-type used to define.
+//
+// wrongMathError 只有 name 和 err，而且沒有 Unwrap，errors.Is、errors.As 看不到裡面的錯誤。
+// richerr.Error 多了：
+//   - Code：有型別的錯誤代碼，errors.Is(err, richerr.Domain) 就能判斷種類
+//   - 建立時自動記錄呼叫堆疊，用 %+v 印出來
+//   - Unwrap，所以 errors.Is、errors.As 可以一路往內找
+//   - Join 把多個錯誤合成一個，nil 會被忽略
+//   - Describe 和 MarshalJSON 把錯誤轉成結構化的 JSON，給 API 回應用
+package richerr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Code classifies an error. A Code is itself an error, so
+// errors.Is(err, Domain) reports whether any *Error in err's chain has
+// that code.
+type Code int
+
+const (
+	Unknown Code = iota
+	InvalidArgument
+	Domain // 數學上沒有定義，例如負數開根號
+	OutOfRange
+	NotFound
+	Internal
+)
+
+var codeNames = [...]string{
+	Unknown:         "unknown",
+	InvalidArgument: "invalid_argument",
+	Domain:          "domain",
+	OutOfRange:      "out_of_range",
+	NotFound:        "not_found",
+	Internal:        "internal",
+}
+
+func (c Code) String() string {
+	if c >= 0 && int(c) < len(codeNames) {
+		return codeNames[c]
+	}
+	return "code(" + strconv.Itoa(int(c)) + ")"
+}
+
+func (c Code) Error() string { return c.String() }
+
+func (c Code) MarshalText() ([]byte, error) { return []byte(c.String()), nil }
+
+func (c *Code) UnmarshalText(b []byte) error {
+	for i, n := range codeNames {
+		if n == string(b) {
+			*c = Code(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("richerr: unknown code %q", b)
+}
+
+// Error is an error with a code, the operation that failed, the
+// underlying error and the stack where it was created.
+type Error struct {
+	Code Code
+	Op   string // 對應 wrongMathError 的 name，例如 "sqrt"
+	Err  error
+	// Details 會原樣放進 JSON，例如 {"input": -10}
+	Details map[string]any
+
+	stack []uintptr
+}
+
+// New returns an *Error whose underlying error is errors.New(msg).
+func New(code Code, op, msg string) *Error {
+	return newError(code, op, errors.New(msg))
+}
+
+// Errorf is New with a format string. %w works as in fmt.Errorf.
+func Errorf(code Code, op, format string, args ...any) *Error {
+	return newError(code, op, fmt.Errorf(format, args...))
+}
+
+// Wrap returns an *Error around err, or nil if err is nil.
+// 回傳 error 而不是 *Error，避免 nil 的 *Error 變成不是 nil 的 error。
+func Wrap(code Code, op string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return newError(code, op, err)
+}
+
+func newError(code Code, op string, err error) *Error {
+	var pcs [32]uintptr
+	n := runtime.Callers(3, pcs[:]) // runtime.Callers、newError、New/Errorf/Wrap
+	return &Error{Code: code, Op: op, Err: err, stack: pcs[:n]}
+}
+
+// With adds a detail and returns e.
+func (e *Error) With(key string, value any) *Error {
+	if e.Details == nil {
+		e.Details = map[string]any{}
+	}
+	e.Details[key] = value
+	return e
+}
+
+func (e *Error) Error() string {
+	var b strings.Builder
+	if e.Op != "" {
+		b.WriteString(e.Op + ": ")
+	}
+	b.WriteString(e.Code.String())
+	if e.Err != nil {
+		b.WriteString(": " + e.Err.Error())
+	}
+	return b.String()
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// Is reports whether target is e's Code, or an *Error with the same Code
+// and, if target.Op is set, the same Op.
+func (e *Error) Is(target error) bool {
+	switch t := target.(type) {
+	case Code:
+		return e.Code == t
+	case *Error:
+		return e.Code == t.Code && (t.Op == "" || e.Op == t.Op)
+	}
+	return false
+}
+
+// Frame is one entry of a captured stack.
+type Frame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+func (f Frame) String() string {
+	return fmt.Sprintf("%s\n\t%s:%d", f.Function, f.File, f.Line)
+}
+
+// Stack returns the stack captured when e was created, innermost first.
+func (e *Error) Stack() []Frame {
+	var fs []Frame
+	frames := runtime.CallersFrames(e.stack)
+	for {
+		f, more := frames.Next()
+		if f.Function != "" {
+			fs = append(fs, Frame{Function: f.Function, File: f.File, Line: f.Line})
+		}
+		if !more {
+			return fs
+		}
+	}
+}
+
+// Format implements fmt.Formatter. %v and %s print Error(); %+v also
+// prints the stack, and the stacks of wrapped *Errors.
+func (e *Error) Format(s fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && s.Flag('+'):
+		io.WriteString(s, e.Error())
+		for _, f := range e.Stack() {
+			io.WriteString(s, "\n"+f.String())
+		}
+		var inner *Error
+		if errors.As(e.Err, &inner) {
+			fmt.Fprintf(s, "\ncaused by: %+v", inner)
+		}
+	case verb == 'q':
+		fmt.Fprintf(s, "%q", e.Error())
+	default:
+		io.WriteString(s, e.Error())
+	}
+}
+
+// CodeOf returns the Code of the first *Error in err's chain, Unknown if
+// there is none.
+func CodeOf(err error) Code {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return Unknown
+}
+
+// Join combines errs into one error, dropping nils and flattening nested
+// joins. It returns nil if nothing is left and the error itself if only
+// one is.
+func Join(errs ...error) error {
+	var m Multi
+	for _, err := range errs {
+		switch e := err.(type) {
+		case nil:
+		case Multi:
+			m = append(m, e...)
+		default:
+			m = append(m, err)
+		}
+	}
+	switch len(m) {
+	case 0:
+		return nil
+	case 1:
+		return m[0]
+	}
+	return m
+}
+
+// Multi is several errors reported together. errors.Is and errors.As
+// look at every one of them.
+type Multi []error
+
+func (m Multi) Error() string {
+	msgs := make([]string, len(m))
+	for i, err := range m {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (m Multi) Unwrap() []error { return m }
+
+// Payload is the structured form of an error for API responses.
+type Payload struct {
+	Code    Code           `json:"code"`
+	Op      string         `json:"op,omitempty"`
+	Message string         `json:"message"`
+	Details map[string]any `json:"details,omitempty"`
+	Cause   *Payload       `json:"cause,omitempty"`
+	Errors  []Payload      `json:"errors,omitempty"`
+	Stack   []Frame        `json:"stack,omitempty"`
+}
+
+// Describe converts err to a Payload. Errors that are not *Error get code
+// Unknown. The stack is left out unless withStack is set, so API clients
+// do not see file paths by default. A nil err, including a nil *Error,
+// gives the zero Payload.
+func Describe(err error, withStack bool) Payload {
+	switch e := err.(type) {
+	case nil:
+		return Payload{}
+	case *Error:
+		if e == nil {
+			return Payload{}
+		}
+		p := Payload{Code: e.Code, Op: e.Op, Message: e.Error(), Details: e.Details}
+		if withStack {
+			p.Stack = e.Stack()
+		}
+		if e.Err != nil {
+			c := Describe(e.Err, withStack)
+			p.Cause = &c
+		}
+		return p
+	case Multi:
+		p := Payload{Code: commonCode(e), Message: e.Error()}
+		for _, err := range e {
+			p.Errors = append(p.Errors, Describe(err, withStack))
+		}
+		return p
+	}
+	// errors.Join 之類的 Unwrap() []error 和 Multi 一樣展開到 Errors；
+	// errors.Unwrap 只認得 Unwrap() error，會把裡面的錯誤都丟掉
+	if j, ok := err.(interface{ Unwrap() []error }); ok {
+		errs := j.Unwrap()
+		p := Payload{Code: commonCode(errs), Message: err.Error()}
+		for _, err := range errs {
+			p.Errors = append(p.Errors, Describe(err, withStack))
+		}
+		return p
+	}
+	// 一般的 error 沒有代碼；有 Unwrap 的話繼續往內看
+	p := Payload{Code: CodeOf(err), Message: err.Error()}
+	if inner := errors.Unwrap(err); inner != nil {
+		c := Describe(inner, withStack)
+		p.Cause = &c
+	}
+	return p
+}
+
+// commonCode 所有錯誤代碼都一樣時回傳那個代碼，否則是 Unknown
+func commonCode(errs []error) Code {
+	if len(errs) == 0 {
+		return Unknown
+	}
+	c := CodeOf(errs[0])
+	for _, err := range errs[1:] {
+		if CodeOf(err) != c {
+			return Unknown
+		}
+	}
+	return c
+}
+
+// MarshalJSON renders e as a Payload without the stack.
+func (e *Error) MarshalJSON() ([]byte, error) {
+	return json.Marshal(Describe(e, false))
+}
+
+// MarshalJSON renders m as a Payload without stacks.
+func (m Multi) MarshalJSON() ([]byte, error) {
+	return json.Marshal(Describe(m, false))
+}
@@ -0,0 +1,209 @@
+package richerr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// sqrt 是 005-custom-type 的 sqrt 改用 richerr 的版本
+func sqrt(f float64) (float64, error) {
+	if f < 0 {
+		return 0, Errorf(Domain, "sqrt", "square root of negative number: %v", f).With("input", f)
+	}
+	return math.Sqrt(f), nil
+}
+
+func TestError(t *testing.T) {
+	_, err := sqrt(-10)
+	if x := err.Error(); x != "sqrt: domain: square root of negative number: -10" {
+		t.Error("Expected", "sqrt: domain: square root of negative number: -10", "Got", x)
+	}
+	if x := New(NotFound, "", "no dog").Error(); x != "not_found: no dog" {
+		t.Error("Expected", "not_found: no dog", "Got", x)
+	}
+	if x := Code(99).String(); x != "code(99)" {
+		t.Error("Expected", "code(99)", "Got", x)
+	}
+	if _, err := sqrt(4); err != nil {
+		t.Error("Expected nil Got", err)
+	}
+}
+
+func TestIsAs(t *testing.T) {
+	_, inner := sqrt(-1)
+	err := fmt.Errorf("handler: %w", Wrap(InvalidArgument, "parse", inner))
+
+	type test struct {
+		target error
+		answer bool
+	}
+	tests := []test{
+		{Domain, true},
+		{InvalidArgument, true},
+		{NotFound, false},
+		{&Error{Code: Domain}, true},
+		{&Error{Code: Domain, Op: "sqrt"}, true},
+		{&Error{Code: Domain, Op: "log"}, false},
+		{&Error{Code: InvalidArgument, Op: "parse"}, true},
+	}
+	for _, v := range tests {
+		if x := errors.Is(err, v.target); x != v.answer {
+			t.Error("Target", v.target, "Expected", v.answer, "Got", x)
+		}
+	}
+
+	var e *Error
+	if !errors.As(err, &e) || e.Op != "parse" {
+		t.Error("Expected the outer *Error Got", e)
+	}
+	if x := CodeOf(err); x != InvalidArgument {
+		t.Error("Expected", InvalidArgument, "Got", x)
+	}
+	if x := CodeOf(errors.New("plain")); x != Unknown {
+		t.Error("Expected", Unknown, "Got", x)
+	}
+
+	// 包起來的標準錯誤一樣看得到
+	err = Wrap(NotFound, "open", fs.ErrNotExist)
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Error("Expected fs.ErrNotExist in", err)
+	}
+	if Wrap(Internal, "x", nil) != nil {
+		t.Error("Wrap(nil) should be nil")
+	}
+}
+
+func TestStack(t *testing.T) {
+	_, err := sqrt(-1)
+	var e *Error
+	errors.As(err, &e)
+	st := e.Stack()
+	if len(st) == 0 || !strings.HasSuffix(st[0].Function, ".sqrt") || !strings.HasSuffix(st[0].File, "main_test.go") {
+		t.Fatal("Expected the stack to start in sqrt Got", st)
+	}
+
+	outer := Wrap(Internal, "handler", err)
+	s := fmt.Sprintf("%+v", outer)
+	for _, want := range []string{"handler: internal: sqrt: domain", "TestStack", "caused by: sqrt: domain", ".sqrt\n"} {
+		if !strings.Contains(s, want) {
+			t.Errorf("%%+v does not contain %q:\n%s", want, s)
+		}
+	}
+	if s := fmt.Sprintf("%v", outer); strings.Contains(s, "\n") {
+		t.Error("Expected one line Got", s)
+	}
+}
+
+func TestJoin(t *testing.T) {
+	if Join() != nil || Join(nil, nil) != nil {
+		t.Error("Expected nil")
+	}
+	_, e1 := sqrt(-1)
+	if Join(nil, e1) != e1 {
+		t.Error("Expected the single error back")
+	}
+
+	_, e2 := sqrt(-2)
+	e3 := New(NotFound, "find", "no dog")
+	err := Join(Join(e1, nil, e2), e3)
+	m, ok := err.(Multi)
+	if !ok || len(m) != 3 {
+		t.Fatal("Expected a flat Multi of 3 Got", err)
+	}
+	if !errors.Is(err, Domain) || !errors.Is(err, NotFound) || errors.Is(err, Internal) {
+		t.Error("Expected Is to see every error in", err)
+	}
+	var e *Error
+	if !errors.As(err, &e) || e.Op != "sqrt" {
+		t.Error("Expected As to find the first *Error Got", e)
+	}
+	want := "sqrt: domain: square root of negative number: -1; sqrt: domain: square root of negative number: -2; find: not_found: no dog"
+	if x := err.Error(); x != want {
+		t.Error("Expected", want, "Got", x)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	_, inner := sqrt(-10)
+	err := Join(Wrap(InvalidArgument, "parse", inner), fmt.Errorf("plain: %w", New(NotFound, "find", "no dog")))
+
+	b, jerr := json.Marshal(err)
+	if jerr != nil {
+		t.Fatal(jerr)
+	}
+	var p Payload
+	if jerr := json.Unmarshal(b, &p); jerr != nil {
+		t.Fatal(jerr, string(b))
+	}
+	if p.Code != Unknown || len(p.Errors) != 2 {
+		t.Fatal("Expected a mixed-code multi Got", string(b))
+	}
+	first := p.Errors[0]
+	if first.Code != InvalidArgument || first.Op != "parse" || first.Cause == nil || first.Cause.Code != Domain {
+		t.Error("unexpected first error", string(b))
+	}
+	if first.Cause.Details["input"] != -10.0 {
+		t.Error("Expected details input -10 Got", first.Cause.Details)
+	}
+	second := p.Errors[1]
+	if second.Code != NotFound || second.Message != "plain: find: not_found: no dog" || second.Cause == nil {
+		t.Error("unexpected second error", string(b))
+	}
+	if strings.Contains(string(b), "stack") {
+		t.Error("MarshalJSON should not include the stack", string(b))
+	}
+
+	if d := Describe(inner, true); len(d.Stack) == 0 {
+		t.Error("Expected a stack with withStack")
+	}
+
+	// 標準函式庫的 errors.Join 也要展開，不能因為 errors.Unwrap 看不到就丟掉
+	std := Describe(errors.Join(New(NotFound, "find", "no dog"), New(NotFound, "find", "no cat")), false)
+	if std.Code != NotFound || len(std.Errors) != 2 || std.Errors[1].Message != "find: not_found: no cat" {
+		t.Error("Expected both joined errors with code", NotFound, "Got", std)
+	}
+	if d := Describe(fmt.Errorf("wrap: %w and %w", fs.ErrNotExist, inner), false); len(d.Errors) != 2 || d.Errors[1].Code != Domain {
+		t.Error("Expected both %w errors Got", d)
+	}
+
+	// 呼叫端常常直接把拿到的 err 丟進來，包括 nil
+	if d := Describe(nil, true); !reflect.DeepEqual(d, Payload{}) {
+		t.Error("Expected the zero Payload Got", d)
+	}
+	if d := Describe((*Error)(nil), false); !reflect.DeepEqual(d, Payload{}) {
+		t.Error("Expected the zero Payload Got", d)
+	}
+
+	var c Code
+	if err := c.UnmarshalText([]byte("domain")); err != nil || c != Domain {
+		t.Error("Expected", Domain, "Got", c, err)
+	}
+	if err := c.UnmarshalText([]byte("nope")); err == nil {
+		t.Error("Expected an error for an unknown code")
+	}
+}
+
+func ExampleErrorf() {
+	_, err := sqrt(-10)
+	fmt.Println(err)
+	fmt.Println(errors.Is(err, Domain))
+
+	var e *Error
+	if errors.As(err, &e) {
+		fmt.Println(e.Op, e.Code, e.Details["input"])
+	}
+
+	b, _ := json.Marshal(err)
+	fmt.Println(string(b))
+	// Output:
+	// sqrt: domain: square root of negative number: -10
+	// true
+	// sqrt domain -10
+	// {"code":"domain","op":"sqrt","message":"sqrt: domain: square root of negative number: -10","details":{"input":-10},"cause":{"code":"unknown","message":"square root of negative number: -10"}}
+}
@@ -0,0 +1,178 @@
+// Package safemath wraps math functions so that invalid input returns an
+// error instead of NaN, ±Inf or a panic.
+//
+// 234 的 sqrt 只用 fmt.Errorf 拒絕負數，呼叫端只能比對字串。這裡每個函式都回傳
+// *richerr.Error，裡面包著下面其中一個 sentinel，兩種判斷方式都可以：
+//
+//	errors.Is(err, safemath.ErrDomain)
+//	errors.Is(err, richerr.Domain)
+//
+// 需要負數開根號之類的複數結果時，用 SqrtComplex、LogComplex 等 Complex 版本，不會回傳錯誤。
+package safemath
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/cmplx"
+	"reflect"
+
+	"github.com/andyrestart9/animalPackage/234-errors-with-info/richerr"
+	"golang.org/x/exp/constraints"
+)
+
+// Sentinel errors wrapped by every error this package returns.
+var (
+	ErrDomain       = errors.New("safemath: argument outside the domain")
+	ErrDivideByZero = errors.New("safemath: division by zero")
+	ErrOverflow     = errors.New("safemath: result overflows")
+)
+
+// Number is any integer or floating-point type.
+type Number interface {
+	constraints.Integer | constraints.Float
+}
+
+// fail 建立 richerr.Error，代碼跟著 sentinel 決定，輸入值放在 Details
+// 輸入值存成字串：NaN 和 ±Inf 沒辦法編成 JSON，會讓 json.Marshal(err) 失敗
+func fail(op string, sentinel error, format string, args ...any) error {
+	code := richerr.Domain
+	if sentinel == ErrOverflow {
+		code = richerr.OutOfRange
+	}
+	e := richerr.Errorf(code, op, "%w: "+format, append([]any{sentinel}, args...)...)
+	for i, a := range args {
+		e.With(fmt.Sprintf("arg%d", i), fmt.Sprint(a))
+	}
+	return e
+}
+
+// Sqrt returns the square root of x. Negative x and NaN are ErrDomain.
+func Sqrt(x float64) (float64, error) {
+	if x < 0 || math.IsNaN(x) {
+		return math.NaN(), fail("sqrt", ErrDomain, "square root of %v", x)
+	}
+	return math.Sqrt(x), nil
+}
+
+// Log returns the natural logarithm of x. x <= 0 and NaN are ErrDomain.
+func Log(x float64) (float64, error) {
+	if x <= 0 || math.IsNaN(x) {
+		return math.NaN(), fail("log", ErrDomain, "logarithm of %v", x)
+	}
+	return math.Log(x), nil
+}
+
+// Asin returns the arcsine of x. |x| > 1 and NaN are ErrDomain.
+func Asin(x float64) (float64, error) {
+	if !(x >= -1 && x <= 1) {
+		return math.NaN(), fail("asin", ErrDomain, "arcsine of %v", x)
+	}
+	return math.Asin(x), nil
+}
+
+// Acos returns the arccosine of x. |x| > 1 and NaN are ErrDomain.
+func Acos(x float64) (float64, error) {
+	if !(x >= -1 && x <= 1) {
+		return math.NaN(), fail("acos", ErrDomain, "arccosine of %v", x)
+	}
+	return math.Acos(x), nil
+}
+
+// Pow returns x**y.
+//   - 負數的非整數次方（實數裡沒有定義）是 ErrDomain
+//   - 0 的負數次方是 ErrDivideByZero
+//   - 有限的輸入得到 ±Inf 是 ErrOverflow
+func Pow(x, y float64) (float64, error) {
+	switch {
+	case math.IsNaN(x) || math.IsNaN(y):
+		return math.NaN(), fail("pow", ErrDomain, "%v ** %v", x, y)
+	case x < 0 && y != math.Trunc(y) && !math.IsInf(y, 0):
+		return math.NaN(), fail("pow", ErrDomain, "negative base %v to non-integer power %v", x, y)
+	case x == 0 && y < 0:
+		return math.NaN(), fail("pow", ErrDivideByZero, "0 ** %v", y)
+	}
+	r := math.Pow(x, y)
+	if math.IsInf(r, 0) && !math.IsInf(x, 0) && !math.IsInf(y, 0) {
+		return r, fail("pow", ErrOverflow, "%v ** %v", x, y)
+	}
+	return r, nil
+}
+
+// Div returns a / b. b == 0 is ErrDivideByZero. For integers the
+// smallest signed value divided by -1 is ErrOverflow; for floats a
+// finite quotient that rounds to ±Inf is, and a NaN operand or ±Inf / ±Inf
+// is ErrDomain.
+func Div[T Number](a, b T) (T, error) {
+	k := reflect.TypeFor[T]().Kind()
+	isFloat := k == reflect.Float32 || k == reflect.Float64
+	// 結果會是 NaN 的情況先擋下來，NaN / 0 也算在這裡
+	if isFloat {
+		fa, fb := float64(a), float64(b)
+		if math.IsNaN(fa) || math.IsNaN(fb) || (math.IsInf(fa, 0) && math.IsInf(fb, 0)) {
+			return T(math.NaN()), fail("div", ErrDomain, "%v / %v", a, b)
+		}
+	}
+	if b == 0 {
+		return 0, fail("div", ErrDivideByZero, "%v / %v", a, b)
+	}
+	if isFloat {
+		q := a / b
+		if math.IsInf(float64(q), 0) && !math.IsInf(float64(a), 0) {
+			return q, fail("div", ErrOverflow, "%v / %v", a, b)
+		}
+		return q, nil
+	}
+	// 有號整數的最小值除以 -1 會繞回自己
+	if b == minusOne[T]() && a != 0 && a == -a {
+		return a, fail("div", ErrOverflow, "%v / %v", a, b)
+	}
+	return a / b, nil
+}
+
+// Mod returns a % b with Go's sign rules: the result has the sign of a.
+// b == 0 is ErrDivideByZero.
+func Mod[T constraints.Integer](a, b T) (T, error) {
+	if b == 0 {
+		return 0, fail("mod", ErrDivideByZero, "%v %% %v", a, b)
+	}
+	return a % b, nil
+}
+
+// minusOne 回傳 T 的 -1；無號整數沒有 -1，回傳 0（b 不會是 0，所以永遠不相等）
+func minusOne[T Number]() T {
+	var m T
+	m--
+	if m > 0 {
+		return 0
+	}
+	return m
+}
+
+// SqrtComplex returns the complex square root of x, so negative x gives
+// an imaginary result instead of an error.
+func SqrtComplex(x float64) complex128 {
+	return cmplx.Sqrt(complex(x, 0))
+}
+
+// LogComplex returns the principal complex logarithm of x. Negative x
+// gives ln|x| + πi and x == 0 gives -Inf.
+func LogComplex(x float64) complex128 {
+	return cmplx.Log(complex(x, 0))
+}
+
+// AsinComplex returns the complex arcsine of x, defined for |x| > 1.
+func AsinComplex(x float64) complex128 {
+	return cmplx.Asin(complex(x, 0))
+}
+
+// AcosComplex returns the complex arccosine of x, defined for |x| > 1.
+func AcosComplex(x float64) complex128 {
+	return cmplx.Acos(complex(x, 0))
+}
+
+// PowComplex returns x**y on the principal branch, so a negative base
+// with a non-integer exponent has a complex result.
+func PowComplex(x, y float64) complex128 {
+	return cmplx.Pow(complex(x, 0), complex(y, 0))
+}
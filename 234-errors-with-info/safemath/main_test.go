@@ -0,0 +1,176 @@
+package safemath
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/cmplx"
+	"strings"
+	"testing"
+
+	"github.com/andyrestart9/animalPackage/234-errors-with-info/richerr"
+)
+
+type test struct {
+	name   string
+	f      func() (float64, error)
+	answer float64
+	err    error
+}
+
+func run(t *testing.T, tests []test) {
+	t.Helper()
+	for _, v := range tests {
+		x, err := v.f()
+		if !errors.Is(err, v.err) || (v.err == nil && err != nil) {
+			t.Error(v.name, "Expected error", v.err, "Got", err)
+			continue
+		}
+		if v.err == nil && math.Abs(x-v.answer) > 1e-12 {
+			t.Error(v.name, "Expected", v.answer, "Got", x)
+		}
+	}
+}
+
+func TestFloat(t *testing.T) {
+	nan, inf := math.NaN(), math.Inf(1)
+	run(t, []test{
+		{"Sqrt(4)", func() (float64, error) { return Sqrt(4) }, 2, nil},
+		{"Sqrt(0)", func() (float64, error) { return Sqrt(0) }, 0, nil},
+		{"Sqrt(-10)", func() (float64, error) { return Sqrt(-10) }, 0, ErrDomain},
+		{"Sqrt(NaN)", func() (float64, error) { return Sqrt(nan) }, 0, ErrDomain},
+		{"Log(e)", func() (float64, error) { return Log(math.E) }, 1, nil},
+		{"Log(0)", func() (float64, error) { return Log(0) }, 0, ErrDomain},
+		{"Log(-1)", func() (float64, error) { return Log(-1) }, 0, ErrDomain},
+		{"Asin(1)", func() (float64, error) { return Asin(1) }, math.Pi / 2, nil},
+		{"Asin(1.5)", func() (float64, error) { return Asin(1.5) }, 0, ErrDomain},
+		{"Acos(-1)", func() (float64, error) { return Acos(-1) }, math.Pi, nil},
+		{"Acos(-2)", func() (float64, error) { return Acos(-2) }, 0, ErrDomain},
+		{"Acos(NaN)", func() (float64, error) { return Acos(nan) }, 0, ErrDomain},
+		{"Pow(2, 10)", func() (float64, error) { return Pow(2, 10) }, 1024, nil},
+		{"Pow(-2, 3)", func() (float64, error) { return Pow(-2, 3) }, -8, nil},
+		{"Pow(-8, 1/3)", func() (float64, error) { return Pow(-8, 1.0/3) }, 0, ErrDomain},
+		{"Pow(0, -1)", func() (float64, error) { return Pow(0, -1) }, 0, ErrDivideByZero},
+		{"Pow(10, 400)", func() (float64, error) { return Pow(10, 400) }, 0, ErrOverflow},
+		{"Pow(2, Inf)", func() (float64, error) { return Pow(2, inf) }, inf, nil},
+		{"Div(1, 4)", func() (float64, error) { return Div(1.0, 4) }, 0.25, nil},
+		{"Div(1, 0)", func() (float64, error) { return Div(1.0, 0) }, 0, ErrDivideByZero},
+		{"Div(Max, 0.5)", func() (float64, error) { return Div(math.MaxFloat64, 0.5) }, 0, ErrOverflow},
+		{"Div(Inf, 2)", func() (float64, error) { return Div(inf, 2) }, inf, nil},
+		{"Div(Inf, -Inf)", func() (float64, error) { return Div(inf, -inf) }, 0, ErrDomain},
+		{"Div(NaN, 1)", func() (float64, error) { return Div(nan, 1) }, 0, ErrDomain},
+		{"Div(1, NaN)", func() (float64, error) { return Div(1, nan) }, 0, ErrDomain},
+		{"Div(NaN, 0)", func() (float64, error) { return Div(nan, 0) }, 0, ErrDomain},
+	})
+}
+
+func TestInteger(t *testing.T) {
+	if x, err := Div(7, 2); x != 3 || err != nil {
+		t.Error("Expected", 3, "Got", x, err)
+	}
+	if _, err := Div(7, 0); !errors.Is(err, ErrDivideByZero) {
+		t.Error("Expected", ErrDivideByZero, "Got", err)
+	}
+	if _, err := Div[int8](math.MinInt8, -1); !errors.Is(err, ErrOverflow) {
+		t.Error("Expected", ErrOverflow, "Got", err)
+	}
+	if x, err := Div[int8](math.MinInt8, 1); x != math.MinInt8 || err != nil {
+		t.Error("Expected", math.MinInt8, "Got", x, err)
+	}
+	if x, err := Div[uint8](255, 255); x != 1 || err != nil {
+		t.Error("Expected", 1, "Got", x, err)
+	}
+
+	type modTest struct {
+		a, b, answer int
+	}
+	for _, v := range []modTest{{7, 3, 1}, {-7, 3, -1}, {7, -3, 1}, {math.MinInt, -1, 0}} {
+		if x, err := Mod(v.a, v.b); x != v.answer || err != nil {
+			t.Error("Mod", v.a, v.b, "Expected", v.answer, "Got", x, err)
+		}
+	}
+	if _, err := Mod[uint](7, 0); !errors.Is(err, ErrDivideByZero) {
+		t.Error("Expected", ErrDivideByZero, "Got", err)
+	}
+}
+
+func TestRichErr(t *testing.T) {
+	_, err := Sqrt(-10)
+	if !errors.Is(err, richerr.Domain) {
+		t.Error("Expected code", richerr.Domain, "Got", richerr.CodeOf(err))
+	}
+	var e *richerr.Error
+	if !errors.As(err, &e) || e.Op != "sqrt" || e.Details["arg0"] != "-10" {
+		t.Error("Expected op sqrt with arg0 -10 Got", e)
+	}
+	if _, err := Pow(10, 400); richerr.CodeOf(err) != richerr.OutOfRange {
+		t.Error("Expected code", richerr.OutOfRange, "Got", richerr.CodeOf(err))
+	}
+}
+
+// 非有限的輸入也要能編成 JSON
+func TestMarshalNonFinite(t *testing.T) {
+	_, e1 := Sqrt(math.NaN())
+	_, e2 := Pow(math.NaN(), 1)
+	_, e3 := Div(math.Inf(1), 0.0)
+	for _, err := range []error{e1, e2, e3} {
+		data, merr := json.Marshal(err)
+		if merr != nil {
+			t.Error("Marshal", err, "Got", merr)
+			continue
+		}
+		if !strings.Contains(string(data), `"arg0":`) {
+			t.Error("Expected arg0 in", string(data))
+		}
+	}
+}
+
+func TestComplex(t *testing.T) {
+	type ctest struct {
+		name   string
+		x      complex128
+		answer complex128
+	}
+	tests := []ctest{
+		{"SqrtComplex(-4)", SqrtComplex(-4), 2i},
+		{"SqrtComplex(9)", SqrtComplex(9), 3},
+		{"LogComplex(-1)", LogComplex(-1), complex(0, math.Pi)},
+		{"PowComplex(-8, 1/3)", PowComplex(-8, 1.0/3), complex(1, math.Sqrt(3))},
+		{"AsinComplex(0.5)", AsinComplex(0.5), complex(math.Asin(0.5), 0)},
+		{"AcosComplex(1)", AcosComplex(1), 0},
+	}
+	for _, v := range tests {
+		if cmplx.Abs(v.x-v.answer) > 1e-12 {
+			t.Error(v.name, "Expected", v.answer, "Got", v.x)
+		}
+	}
+	// |x| > 1 在實數裡沒有定義，複數版本有；sin(asin(2)) 要回到 2
+	if x := cmplx.Sin(AsinComplex(2)); cmplx.Abs(x-2) > 1e-12 {
+		t.Error("Expected", 2, "Got", x)
+	}
+	if x := cmplx.Cos(AcosComplex(-3)); cmplx.Abs(x+3) > 1e-12 {
+		t.Error("Expected", -3, "Got", x)
+	}
+}
+
+func ExampleSqrt() {
+	_, err := Sqrt(-10)
+	fmt.Println(err)
+	fmt.Println(errors.Is(err, ErrDomain))
+	fmt.Println(SqrtComplex(-10))
+	// Output:
+	// sqrt: domain: safemath: argument outside the domain: square root of -10
+	// true
+	// (0+3.1622776601683795i)
+}
+
+func ExampleDiv() {
+	_, err := Div(10, 0)
+	fmt.Println(errors.Is(err, ErrDivideByZero))
+	_, err = Div[int8](-128, -1)
+	fmt.Println(errors.Is(err, ErrOverflow))
+	// Output:
+	// true
+	// true
+}
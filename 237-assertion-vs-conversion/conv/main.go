@@ -0,0 +1,340 @@
+// Package conv converts values held in an any to concrete types without
+// panicking.
+//
+// 237 比較了轉換 float64(i) 和斷言 x.(string)：斷言只能取出「原本就是」那個型別的值，
+// 型別不對就 panic；轉換要在編譯期就知道兩邊的型別。從 JSON、設定檔拿到 any 的時候兩個都不夠用。
+// As[T] 先試斷言，不行再依 T 的種類轉換：
+//   - 數字之間：檢查範圍，Lossless 模式下連精度也檢查（3.5 不能變成 int）
+//   - 字串轉數字、bool：用 strconv 解析，十進位，或明確寫出前綴的 "0x10"、"0b10"、"0o10"
+//     （"010" 是 10，不是八進位）
+//   - 數字轉字串：十進位，不是 string(rune)
+//   - []any 或任何切片轉成 []T：逐一轉換，錯誤會指出是第幾個
+//
+// 失敗時回傳 *ConversionError，可以用 errors.Is 判斷是 ErrType、ErrRange、ErrPrecision、ErrSyntax 還是 ErrNil。
+package conv
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+// Mode selects how strict numeric conversions are.
+type Mode int
+
+const (
+	// Lossless fails with ErrPrecision instead of dropping a fraction or
+	// rounding, e.g. 3.5 to int or 1<<60 to float64.
+	Lossless Mode = iota
+	// Lossy truncates fractions toward zero and rounds to the nearest
+	// float. Out-of-range values are still ErrRange.
+	Lossy
+)
+
+func (m Mode) String() string {
+	if m == Lossy {
+		return "lossy"
+	}
+	return "lossless"
+}
+
+// Reasons a conversion fails. ConversionError wraps one of them.
+var (
+	ErrType      = errors.New("unsupported conversion")
+	ErrRange     = errors.New("value out of range")
+	ErrPrecision = errors.New("value would lose precision")
+	ErrSyntax    = errors.New("invalid syntax")
+	ErrNil       = errors.New("nil value")
+)
+
+// ConversionError describes a failed conversion.
+type ConversionError struct {
+	Value any
+	To    reflect.Type
+	// Path 是切片裡出錯的位置，例如 "[2]"；不是切片時是空字串
+	Path string
+	Err  error
+}
+
+func (e *ConversionError) Error() string {
+	from := "nil"
+	if e.Value != nil {
+		from = fmt.Sprintf("%T %#v", e.Value, e.Value)
+	}
+	at := ""
+	if e.Path != "" {
+		at = " at " + e.Path
+	}
+	return fmt.Sprintf("conv: cannot convert %s to %v%s: %v", from, e.To, at, e.Err)
+}
+
+func (e *ConversionError) Unwrap() error { return e.Err }
+
+// As converts v to T in Lossless mode.
+func As[T any](v any) (T, error) {
+	return Convert[T](v, Lossless)
+}
+
+// AsLossy converts v to T in Lossy mode.
+func AsLossy[T any](v any) (T, error) {
+	return Convert[T](v, Lossy)
+}
+
+// Convert converts v to T. If v already holds a T it is returned as is.
+func Convert[T any](v any, m Mode) (T, error) {
+	if t, ok := v.(T); ok {
+		return t, nil
+	}
+	var zero T
+	rv, err := convert(v, reflect.TypeFor[T](), m)
+	if err != nil {
+		return zero, err
+	}
+	// T 是介面型別而 v 是 nil 時 rv 是 nil 介面，單值斷言會 panic
+	t, _ := rv.Interface().(T)
+	return t, nil
+}
+
+// Slice converts every element of the slice or array v to T. It is
+// Convert[[]T] spelled out for readability.
+func Slice[T any](v any, m Mode) ([]T, error) {
+	return Convert[[]T](v, m)
+}
+
+// convert 把 v 轉成 to 型別
+func convert(v any, to reflect.Type, m Mode) (reflect.Value, error) {
+	fail := func(err error) (reflect.Value, error) {
+		return reflect.Value{}, &ConversionError{Value: v, To: to, Err: err}
+	}
+
+	if v == nil {
+		switch to.Kind() {
+		case reflect.Interface, reflect.Pointer, reflect.Slice, reflect.Map, reflect.Func, reflect.Chan:
+			return reflect.Zero(to), nil
+		}
+		return fail(ErrNil)
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Type() == to {
+		return rv, nil
+	}
+	if to.Kind() == reflect.Interface {
+		if rv.Type().Implements(to) {
+			out := reflect.New(to).Elem()
+			out.Set(rv)
+			return out, nil
+		}
+		return fail(ErrType)
+	}
+
+	switch rv.Kind() {
+	case reflect.String:
+		return fromString(rv.String(), to, m, fail)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return fromInt(rv.Int(), to, m, fail)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return fromUint(rv.Uint(), to, m, fail)
+	case reflect.Float32, reflect.Float64:
+		return fromFloat(rv.Float(), to, m, fail)
+	case reflect.Bool:
+		switch to.Kind() {
+		case reflect.Bool:
+			return reflect.ValueOf(rv.Bool()).Convert(to), nil
+		case reflect.String:
+			return reflect.ValueOf(strconv.FormatBool(rv.Bool())).Convert(to), nil
+		}
+	case reflect.Slice, reflect.Array:
+		if to.Kind() == reflect.Slice {
+			return fromSlice(rv, to, m)
+		}
+	}
+	// 其他情況（例如具名型別之間）交給 Go 本身的轉換規則
+	if rv.Type().ConvertibleTo(to) && rv.Kind() == to.Kind() {
+		return rv.Convert(to), nil
+	}
+	return fail(ErrType)
+}
+
+type failFunc func(error) (reflect.Value, error)
+
+func fromInt(i int64, to reflect.Type, m Mode, fail failFunc) (reflect.Value, error) {
+	out := reflect.New(to).Elem()
+	switch to.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if out.OverflowInt(i) {
+			return fail(ErrRange)
+		}
+		out.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if i < 0 || out.OverflowUint(uint64(i)) {
+			return fail(ErrRange)
+		}
+		out.SetUint(uint64(i))
+	case reflect.Float32, reflect.Float64:
+		f := float64(i)
+		if to.Kind() == reflect.Float32 {
+			f = float64(float32(f))
+		}
+		// 超過 2^53 的整數 float64 放不下每一位；f 可能剛好是 2^63，不能直接轉回 int64
+		if m == Lossless && (f >= math.MaxInt64 || int64(f) != i) {
+			return fail(ErrPrecision)
+		}
+		out.SetFloat(f)
+	case reflect.String:
+		out.SetString(strconv.FormatInt(i, 10))
+	default:
+		return fail(ErrType)
+	}
+	return out, nil
+}
+
+func fromUint(u uint64, to reflect.Type, m Mode, fail failFunc) (reflect.Value, error) {
+	out := reflect.New(to).Elem()
+	switch to.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if u > math.MaxInt64 || out.OverflowInt(int64(u)) {
+			return fail(ErrRange)
+		}
+		out.SetInt(int64(u))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if out.OverflowUint(u) {
+			return fail(ErrRange)
+		}
+		out.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f := float64(u)
+		if to.Kind() == reflect.Float32 {
+			f = float64(float32(f))
+		}
+		if m == Lossless && (f >= math.MaxUint64 || uint64(f) != u) {
+			return fail(ErrPrecision)
+		}
+		out.SetFloat(f)
+	case reflect.String:
+		out.SetString(strconv.FormatUint(u, 10))
+	default:
+		return fail(ErrType)
+	}
+	return out, nil
+}
+
+func fromFloat(f float64, to reflect.Type, m Mode, fail failFunc) (reflect.Value, error) {
+	out := reflect.New(to).Elem()
+	switch to.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return fail(ErrRange)
+		}
+		t := math.Trunc(f)
+		if t != f && m == Lossless {
+			return fail(ErrPrecision)
+		}
+		// 先用浮點數比較範圍，再交給整數的版本做精確的檢查
+		if t >= -(1<<63) && t < 1<<63 {
+			return fromInt(int64(t), to, m, fail)
+		}
+		if t >= 0 && t < 1<<64 {
+			return fromUint(uint64(t), to, m, fail)
+		}
+		return fail(ErrRange)
+	case reflect.Float32, reflect.Float64:
+		if out.OverflowFloat(f) {
+			return fail(ErrRange)
+		}
+		if to.Kind() == reflect.Float32 && m == Lossless && !math.IsNaN(f) && float64(float32(f)) != f {
+			return fail(ErrPrecision)
+		}
+		out.SetFloat(f)
+	case reflect.String:
+		out.SetString(strconv.FormatFloat(f, 'g', -1, 64))
+	default:
+		return fail(ErrType)
+	}
+	return out, nil
+}
+
+func fromString(s string, to reflect.Type, m Mode, fail failFunc) (reflect.Value, error) {
+	switch to.Kind() {
+	case reflect.String:
+		return reflect.ValueOf(s).Convert(to), nil
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return fail(ErrSyntax)
+		}
+		return reflect.ValueOf(b).Convert(to), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if i, err := strconv.ParseInt(s, intBase(s), 64); err == nil {
+			return fromInt(i, to, m, fail)
+		} else if errors.Is(err, strconv.ErrRange) {
+			return fail(ErrRange)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if u, err := strconv.ParseUint(s, intBase(s), 64); err == nil {
+			return fromUint(u, to, m, fail)
+		} else if errors.Is(err, strconv.ErrRange) {
+			return fail(ErrRange)
+		}
+	case reflect.Float32, reflect.Float64:
+		// 先當整數解析：ParseFloat 的十六進位一定要有 p 指數，"0x10" 會失敗
+		if i, err := strconv.ParseInt(s, intBase(s), 64); err == nil {
+			return fromInt(i, to, m, fail)
+		}
+		if u, err := strconv.ParseUint(s, intBase(s), 64); err == nil {
+			return fromUint(u, to, m, fail)
+		}
+	default:
+		return fail(ErrType)
+	}
+	// 整數解析失敗時再試浮點數，"3.0" 轉 int 在 Lossless 下也可以
+	// 目標是 float32 時直接用 32 位元解析，"0.1" 才不會先變成 float64 再被當成精度損失
+	bits := 64
+	if to.Kind() == reflect.Float32 {
+		bits = 32
+	}
+	f, err := strconv.ParseFloat(s, bits)
+	if errors.Is(err, strconv.ErrRange) {
+		return fail(ErrRange)
+	}
+	if err != nil {
+		return fail(ErrSyntax)
+	}
+	return fromFloat(f, to, m, fail)
+}
+
+// intBase 回傳解析 s 用的進位：只有明確寫出 0x、0b、0o 前綴時才交給 strconv 判斷，
+// 其他一律十進位，設定檔裡的 "010" 是 10 而不是八進位的 8
+func intBase(s string) int {
+	if len(s) > 0 && (s[0] == '+' || s[0] == '-') {
+		s = s[1:]
+	}
+	if len(s) > 2 && s[0] == '0' {
+		switch s[1] {
+		case 'x', 'X', 'b', 'B', 'o', 'O':
+			return 0
+		}
+	}
+	return 10
+}
+
+func fromSlice(rv reflect.Value, to reflect.Type, m Mode) (reflect.Value, error) {
+	if rv.Kind() == reflect.Slice && rv.IsNil() {
+		return reflect.Zero(to), nil
+	}
+	out := reflect.MakeSlice(to, rv.Len(), rv.Len())
+	for i := range rv.Len() {
+		ev, err := convert(rv.Index(i).Interface(), to.Elem(), m)
+		if err != nil {
+			var ce *ConversionError
+			if errors.As(err, &ce) {
+				ce.Path = fmt.Sprintf("[%d]%s", i, ce.Path)
+			}
+			return reflect.Value{}, err
+		}
+		out.Index(i).Set(ev)
+	}
+	return out, nil
+}
@@ -0,0 +1,191 @@
+package conv
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"testing"
+)
+
+type celsius float64
+
+type test struct {
+	name   string
+	f      func() (any, error)
+	answer any
+	err    error
+}
+
+func wrap[T any](v T, err error) (any, error) { return v, err }
+
+func run(t *testing.T, tests []test) {
+	t.Helper()
+	for _, v := range tests {
+		x, err := v.f()
+		if v.err != nil {
+			if !errors.Is(err, v.err) {
+				t.Error(v.name, "Expected", v.err, "Got", x, err)
+			}
+			continue
+		}
+		if err != nil || fmt.Sprintf("%T %v", x, x) != fmt.Sprintf("%T %v", v.answer, v.answer) {
+			t.Error(v.name, "Expected", v.answer, "Got", x, err)
+		}
+	}
+}
+
+func TestAssertion(t *testing.T) {
+	var x any = "hello, world"
+	run(t, []test{
+		{"string", func() (any, error) { return wrap(As[string](x)) }, "hello, world", nil},
+		{"any", func() (any, error) { return wrap(As[any](x)) }, "hello, world", nil},
+		{"Stringer", func() (any, error) { return wrap(As[fmt.Stringer](Lossy)) }, Lossy, nil},
+		{"error from string", func() (any, error) { return wrap(As[error](x)) }, nil, ErrType},
+		{"nil to int", func() (any, error) { return wrap(As[int](nil)) }, nil, ErrNil},
+		{"nil to []int", func() (any, error) { return wrap(As[[]int](nil)) }, []int(nil), nil},
+		{"nil to *int", func() (any, error) { return wrap(As[*int](nil)) }, (*int)(nil), nil},
+		{"nil to error", func() (any, error) { return wrap(As[error](nil)) }, nil, nil},
+		{"nil to any", func() (any, error) { return wrap(As[any](nil)) }, nil, nil},
+		{"struct", func() (any, error) { return wrap(As[int](struct{}{})) }, nil, ErrType},
+	})
+}
+
+func TestNumeric(t *testing.T) {
+	run(t, []test{
+		// 放大 (widening)
+		{"int8 to int64", func() (any, error) { return wrap(As[int64](int8(-5))) }, int64(-5), nil},
+		{"int to float64", func() (any, error) { return wrap(As[float64](42)) }, 42.0, nil},
+		{"float32 to float64", func() (any, error) { return wrap(As[float64](float32(0.5))) }, 0.5, nil},
+		{"float64 to celsius", func() (any, error) { return wrap(As[celsius](36.6)) }, celsius(36.6), nil},
+		// 縮小 (narrowing) 要檢查範圍
+		{"300 to int8", func() (any, error) { return wrap(As[int8](300)) }, nil, ErrRange},
+		{"127 to int8", func() (any, error) { return wrap(As[int8](int64(127))) }, int8(127), nil},
+		{"-1 to uint", func() (any, error) { return wrap(As[uint](-1)) }, nil, ErrRange},
+		{"MaxUint64 to int64", func() (any, error) { return wrap(As[int64](uint64(math.MaxUint64))) }, nil, ErrRange},
+		{"MaxUint64 to uint64", func() (any, error) { return wrap(As[uint64](uint64(math.MaxUint64))) }, uint64(math.MaxUint64), nil},
+		{"1e300 to float32", func() (any, error) { return wrap(As[float32](1e300)) }, nil, ErrRange},
+		{"1e300 to int", func() (any, error) { return wrap(AsLossy[int](1e300)) }, nil, ErrRange},
+		{"NaN to int", func() (any, error) { return wrap(AsLossy[int](math.NaN())) }, nil, ErrRange},
+		{"-1.5 to uint lossy", func() (any, error) { return wrap(AsLossy[uint](-1.5)) }, nil, ErrRange},
+		{"1e19 to uint64", func() (any, error) { return wrap(As[uint64](1e19)) }, uint64(1e19), nil},
+		// 精度
+		{"3.5 to int", func() (any, error) { return wrap(As[int](3.5)) }, nil, ErrPrecision},
+		{"3.5 to int lossy", func() (any, error) { return wrap(AsLossy[int](3.5)) }, 3, nil},
+		{"-3.5 to int lossy", func() (any, error) { return wrap(AsLossy[int](-3.5)) }, -3, nil},
+		{"3.0 to int", func() (any, error) { return wrap(As[int](3.0)) }, 3, nil},
+		{"2^53+1 to float64", func() (any, error) { return wrap(As[float64](1<<53 + 1)) }, nil, ErrPrecision},
+		{"2^53+1 to float64 lossy", func() (any, error) { return wrap(AsLossy[float64](1<<53 + 1)) }, float64(1 << 53), nil},
+		{"MaxInt64 to float64", func() (any, error) { return wrap(As[float64](int64(math.MaxInt64))) }, nil, ErrPrecision},
+		{"0.1 to float32", func() (any, error) { return wrap(As[float32](0.1)) }, nil, ErrPrecision},
+		{"0.1 to float32 lossy", func() (any, error) { return wrap(AsLossy[float32](0.1)) }, float32(0.1), nil},
+		{"0.5 to float32", func() (any, error) { return wrap(As[float32](0.5)) }, float32(0.5), nil},
+	})
+}
+
+func TestString(t *testing.T) {
+	run(t, []test{
+		{"\"42\" to int", func() (any, error) { return wrap(As[int]("42")) }, 42, nil},
+		{"\"0x10\" to uint8", func() (any, error) { return wrap(As[uint8]("0x10")) }, uint8(16), nil},
+		{"\"-0x10\" to int", func() (any, error) { return wrap(As[int]("-0x10")) }, -16, nil},
+		{"\"0b101\" to int", func() (any, error) { return wrap(As[int]("0b101")) }, 5, nil},
+		{"\"0o17\" to uint", func() (any, error) { return wrap(As[uint]("0o17")) }, uint(15), nil},
+		// 沒有明確前綴就是十進位，不是八進位
+		{"\"010\" to int", func() (any, error) { return wrap(As[int]("010")) }, 10, nil},
+		{"\"010\" to uint", func() (any, error) { return wrap(As[uint]("010")) }, uint(10), nil},
+		{"\"010\" to float64", func() (any, error) { return wrap(As[float64]("010")) }, 10.0, nil},
+		{"\"0x10\" to float64", func() (any, error) { return wrap(As[float64]("0x10")) }, 16.0, nil},
+		{"\"18446744073709551615\" to float64", func() (any, error) { return wrap(AsLossy[float64]("18446744073709551615")) }, float64(1 << 64), nil},
+		{"\"0.1\" to float32", func() (any, error) { return wrap(As[float32]("0.1")) }, float32(0.1), nil},
+		{"\"1e39\" to float32", func() (any, error) { return wrap(As[float32]("1e39")) }, nil, ErrRange},
+		{"\"300\" to uint8", func() (any, error) { return wrap(As[uint8]("300")) }, nil, ErrRange},
+		{"\"99999999999999999999\" to int64", func() (any, error) { return wrap(As[int64]("99999999999999999999")) }, nil, ErrRange},
+		{"\"3.0\" to int", func() (any, error) { return wrap(As[int]("3.0")) }, 3, nil},
+		{"\"3.7\" to int", func() (any, error) { return wrap(As[int]("3.7")) }, nil, ErrPrecision},
+		{"\"3.7\" to int lossy", func() (any, error) { return wrap(AsLossy[int]("3.7")) }, 3, nil},
+		{"\"2.5\" to float64", func() (any, error) { return wrap(As[float64]("2.5")) }, 2.5, nil},
+		{"\"1e400\" to float64", func() (any, error) { return wrap(As[float64]("1e400")) }, nil, ErrRange},
+		{"\"abc\" to int", func() (any, error) { return wrap(As[int]("abc")) }, nil, ErrSyntax},
+		{"\"true\" to bool", func() (any, error) { return wrap(As[bool]("true")) }, true, nil},
+		{"\"yes\" to bool", func() (any, error) { return wrap(As[bool]("yes")) }, nil, ErrSyntax},
+		{"\"x\" to struct", func() (any, error) { return wrap(As[struct{}]("x")) }, nil, ErrType},
+		// 數字轉字串是十進位，不是 string(rune(65)) 的 "A"
+		{"65 to string", func() (any, error) { return wrap(As[string](65)) }, "65", nil},
+		{"uint to string", func() (any, error) { return wrap(As[string](uint(7))) }, "7", nil},
+		{"0.25 to string", func() (any, error) { return wrap(As[string](0.25)) }, "0.25", nil},
+		{"bool to string", func() (any, error) { return wrap(As[string](false)) }, "false", nil},
+		{"1 to bool", func() (any, error) { return wrap(As[bool](1)) }, nil, ErrType},
+	})
+}
+
+func TestSlice(t *testing.T) {
+	xs, err := Slice[int]([]any{1, int8(2), "3", 4.0}, Lossless)
+	if err != nil || !slices.Equal(xs, []int{1, 2, 3, 4}) {
+		t.Error("Expected", []int{1, 2, 3, 4}, "Got", xs, err)
+	}
+	fs, err := As[[]float64]([3]int{1, 2, 3})
+	if err != nil || !slices.Equal(fs, []float64{1, 2, 3}) {
+		t.Error("Expected", []float64{1, 2, 3}, "Got", fs, err)
+	}
+	nested, err := As[[][]string]([]any{[]any{1, "a"}, []int{2}})
+	if err != nil || len(nested) != 2 || nested[0][0] != "1" || nested[1][0] != "2" {
+		t.Error("Expected [[1 a] [2]] Got", nested, err)
+	}
+
+	_, err = Slice[int]([]any{1, 2.5}, Lossless)
+	var ce *ConversionError
+	if !errors.As(err, &ce) || ce.Path != "[1]" || !errors.Is(err, ErrPrecision) {
+		t.Error("Expected ErrPrecision at [1] Got", err)
+	}
+	if x, err := Slice[int]([]any{1, 2.5}, Lossy); err != nil || !slices.Equal(x, []int{1, 2}) {
+		t.Error("Expected", []int{1, 2}, "Got", x, err)
+	}
+	_, err = As[[][]int]([]any{[]any{1}, []any{2, "x"}})
+	if !errors.As(err, &ce) || ce.Path != "[1][1]" {
+		t.Error("Expected an error at [1][1] Got", err)
+	}
+	if _, err := Slice[int](42, Lossless); !errors.Is(err, ErrType) {
+		t.Error("Expected", ErrType, "Got", err)
+	}
+}
+
+func TestConversionError(t *testing.T) {
+	_, err := As[int8](300)
+	want := "conv: cannot convert int 300 to int8: value out of range"
+	if err == nil || err.Error() != want {
+		t.Error("Expected", want, "Got", err)
+	}
+	_, err = Slice[int]([]any{"1", "x"}, Lossy)
+	want = `conv: cannot convert string "x" to int at [1]: invalid syntax`
+	if err == nil || err.Error() != want {
+		t.Error("Expected", want, "Got", err)
+	}
+	_, err = As[int](nil)
+	want = "conv: cannot convert nil to int: nil value"
+	if err == nil || err.Error() != want {
+		t.Error("Expected", want, "Got", err)
+	}
+}
+
+func ExampleAs() {
+	var x any = "hello, world"
+
+	// x.(int) 會 panic，As 回傳錯誤
+	_, err := As[int](x)
+	fmt.Println(err)
+
+	// float64(i) 只能用在編譯期就知道的型別，As 可以用在 any
+	var i any = 42
+	f, _ := As[float64](i)
+	fmt.Println(f)
+
+	n, err := As[int](3.5)
+	fmt.Println(n, errors.Is(err, ErrPrecision))
+	n, _ = AsLossy[int](3.5)
+	fmt.Println(n)
+	// Output:
+	// conv: cannot convert string "hello, world" to int: invalid syntax
+	// 42
+	// 0 true
+	// 3
+}
@@ -1,6 +1,10 @@
 package main
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/andyrestart9/animalPackage/237-assertion-vs-conversion/conv"
+)
 
 func main() {
 	// ===== 1. 類型轉換 (Conversion) =====
@@ -23,9 +27,20 @@ func main() {
 
 	// 若想示範 panic 並捕捉，可用下面函式 safeAssert，示範不匹配的類型斷言會 panic
 	safeAssert(x)
+
+	// ===== 3. 不會 panic 的斷言＋轉換 =====
+	// conv.As 先試斷言，型別不對時再依目標型別轉換，失敗就回傳錯誤
+	if _, err := conv.As[int](x); err != nil {
+		fmt.Println("conv.As failed:", err)
+	}
+	var y interface{} = "42"
+	if n, err := conv.As[int](y); err == nil {
+		fmt.Println("conv.As succeeded:", n)
+	}
 }
 
 // safeAssert 演示不帶 ok 的斷言失敗時會 panic，並以 recover 捕捉
+// 實際專案可以用 233-002-recover/safe 的 safe.Safely 包起來，panic 會變成帶有 panic 值和堆疊的錯誤
 func safeAssert(v interface{}) {
 	// 註冊 defer，捕捉後續的 panic，避免程式崩潰
 	defer func() {
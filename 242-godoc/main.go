@@ -4,10 +4,17 @@ import (
 	"fmt"
 
 	"github.com/andyrestart9/animalPackage/242-godoc/mymath"
+	"github.com/andyrestart9/animalPackage/242-godoc/mymath/stats"
 )
 
 func main() {
 	fmt.Println("2 + 3 =", mymath.Sum(2, 3))
 	fmt.Println("4 + 7 =", mymath.Sum(4, 7))
 	fmt.Println("5 + 9 =", mymath.Sum(5, 9))
+
+	xs := []int{2, 4, 4, 4, 5, 5, 7, 9}
+	mean, _ := stats.Mean(xs)
+	median, _ := stats.Median(xs)
+	sd, _ := stats.StdDev(xs)
+	fmt.Println("mean:", mean, "median:", median, "stddev:", sd)
 }
@@ -0,0 +1,143 @@
+// Package gsum provides generic summation for any integer or float type.
+//
+// mymath.Sum 只能加 int，而且溢位時會默默繞回 (wrap around)。
+// gsum 提供四種模式：
+//
+//	Sum         跟 mymath.Sum 一樣，溢位就繞回
+//	Checked     溢位時回傳 ErrOverflow
+//	Saturating  溢位時停在型別的最大值或最小值
+//	Compensated 只給浮點數用，用 Kahan/Neumaier 補償減少捨入誤差
+package gsum
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"unsafe"
+
+	"golang.org/x/exp/constraints"
+)
+
+// Number is any integer or floating-point type.
+type Number interface {
+	constraints.Integer | constraints.Float
+}
+
+// ErrOverflow is returned by Checked when the sum does not fit in T.
+var ErrOverflow = errors.New("gsum: overflow")
+
+// Sum adds an unlimited number of values of type T.
+// Like mymath.Sum, integer overflow wraps around.
+func Sum[T Number](xs ...T) T {
+	var sum T
+	for _, v := range xs {
+		sum += v
+	}
+	return sum
+}
+
+// Checked adds xs and returns an error wrapping ErrOverflow as soon as
+// a partial sum does not fit in T. For floats, overflow means two finite
+// values added up to ±Inf.
+func Checked[T Number](xs ...T) (T, error) {
+	var sum T
+	for i, v := range xs {
+		s, ok := add(sum, v)
+		if !ok {
+			return sum, fmt.Errorf("%w: adding %v at index %d to %v", ErrOverflow, v, i, sum)
+		}
+		sum = s
+	}
+	return sum, nil
+}
+
+// Saturating adds xs, clamping every partial sum to the range of T
+// instead of wrapping around. For floats the range is the largest finite
+// value, so finite inputs never produce ±Inf.
+//
+// 注意是「每一步」都夾住，所以 Saturating(127, 1, -1) 對 int8 是 126 而不是 127。
+func Saturating[T Number](xs ...T) T {
+	lo, hi := bounds[T]()
+	var sum T
+	for _, v := range xs {
+		s, ok := add(sum, v)
+		if !ok {
+			if v > 0 {
+				s = hi
+			} else {
+				s = lo
+			}
+		}
+		sum = s
+	}
+	return sum
+}
+
+// Compensated adds xs with Neumaier's variant of Kahan summation, which
+// keeps a running compensation for the low-order bits lost in each addition.
+//
+// 例如 Sum(1e100, 1.0, -1e100) 會得到 0，Compensated 會得到 1。
+func Compensated[F constraints.Float](xs ...F) F {
+	var sum, c F
+	for _, v := range xs {
+		t := sum + v
+		// 兩個數裡比較大的那個決定了被捨掉的是哪一邊的低位
+		if abs(sum) >= abs(v) {
+			c += (sum - t) + v
+		} else {
+			c += (v - t) + sum
+		}
+		sum = t
+	}
+	return sum + c
+}
+
+// add 回傳 a+b，ok 為 false 表示結果超出 T 的範圍
+func add[T Number](a, b T) (s T, ok bool) {
+	s = a + b
+	if isFloat[T]() {
+		lo, hi := bounds[T]()
+		finite := a >= lo && a <= hi && b >= lo && b <= hi
+		return s, !finite || (s >= lo && s <= hi)
+	}
+	// 整數：加正數結果卻變小、加負數結果卻變大，就是繞回了
+	// 無號整數的 b < 0 永遠是 false
+	return s, !(b > 0 && s < a) && !(b < 0 && s > a)
+}
+
+// isFloat 用 1/2 是否為 0 來判斷 T 是不是浮點數
+func isFloat[T Number]() bool {
+	var one T = 1
+	return one/2 != 0
+}
+
+// bounds 回傳 T 能表示的最小值和最大值，浮點數回傳最大的有限值
+func bounds[T Number]() (lo, hi T) {
+	var zero T
+	bits := unsafe.Sizeof(zero) * 8
+
+	if isFloat[T]() {
+		f := math.MaxFloat64
+		if bits == 32 {
+			f = math.MaxFloat32
+		}
+		return T(-f), T(f)
+	}
+
+	// 0 減 1：有號整數得到 -1，無號整數繞回最大值
+	m := zero
+	m--
+	if m > 0 {
+		return 0, m
+	}
+	u := uint64(1)<<(bits-1) - 1
+	hi = T(u)
+	return -hi - 1, hi
+}
+
+func abs[F constraints.Float](x F) F {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
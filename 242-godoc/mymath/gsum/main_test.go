@@ -0,0 +1,134 @@
+package gsum
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/andyrestart9/animalPackage/247-table-tests/sumtest"
+)
+
+// 三種整數模式在不會溢位的情況下都必須通過共用的表格
+func TestSharedTable(t *testing.T) {
+	t.Run("Sum", func(t *testing.T) {
+		sumtest.Run(t, Sum[int])
+	})
+	t.Run("Checked", func(t *testing.T) {
+		sumtest.Run(t, func(xi ...int) int {
+			s, err := Checked(xi...)
+			if err != nil {
+				t.Error("unexpected error:", err)
+			}
+			return s
+		})
+	})
+	t.Run("Saturating", func(t *testing.T) {
+		sumtest.Run(t, Saturating[int])
+	})
+	t.Run("Compensated", func(t *testing.T) {
+		sumtest.Run(t, func(xi ...int) int {
+			fs := make([]float64, len(xi))
+			for i, v := range xi {
+				fs[i] = float64(v)
+			}
+			return int(Compensated(fs...))
+		})
+	})
+}
+
+func TestCheckedOverflow(t *testing.T) {
+	if _, err := Checked[int8](100, 27); err != nil {
+		t.Error("127 fits in int8, got", err)
+	}
+	if s, err := Checked[int8](100, 28); !errors.Is(err, ErrOverflow) {
+		t.Error("Expected ErrOverflow Got", s, err)
+	}
+	if s, err := Checked[int8](-100, -29); !errors.Is(err, ErrOverflow) {
+		t.Error("Expected ErrOverflow Got", s, err)
+	}
+	if s, err := Checked[uint8](200, 56); !errors.Is(err, ErrOverflow) {
+		t.Error("Expected ErrOverflow Got", s, err)
+	}
+	if s, err := Checked(math.MaxInt64, 1); !errors.Is(err, ErrOverflow) {
+		t.Error("Expected ErrOverflow Got", s, err)
+	}
+	if s, err := Checked(math.MaxFloat64, math.MaxFloat64); !errors.Is(err, ErrOverflow) {
+		t.Error("Expected ErrOverflow Got", s, err)
+	}
+	// 輸入本來就是 Inf 不算溢位
+	if s, err := Checked(math.Inf(1), 1); err != nil || !math.IsInf(s, 1) {
+		t.Error("Expected +Inf Got", s, err)
+	}
+}
+
+func TestSaturating(t *testing.T) {
+	type test struct {
+		data   []int8
+		answer int8
+	}
+	tests := []test{
+		{[]int8{100, 100}, 127},
+		{[]int8{-100, -100}, -128},
+		{[]int8{127, 1, -1}, 126},
+		{[]int8{-128, -1, 1}, -127},
+		{[]int8{50, 50}, 100},
+	}
+	for _, v := range tests {
+		x := Saturating(v.data...)
+		if x != v.answer {
+			t.Error("Data", v.data, "Expected", v.answer, "Got", x)
+		}
+	}
+
+	if x := Saturating[uint8](200, 100); x != 255 {
+		t.Error("Expected", 255, "Got", x)
+	}
+	if x := Saturating[float32](math.MaxFloat32, math.MaxFloat32); x != math.MaxFloat32 {
+		t.Error("Expected", float32(math.MaxFloat32), "Got", x)
+	}
+	if x := Saturating(-math.MaxFloat64, -math.MaxFloat64); x != -math.MaxFloat64 {
+		t.Error("Expected", -math.MaxFloat64, "Got", x)
+	}
+}
+
+func TestCompensated(t *testing.T) {
+	if x := Compensated(1e100, 1.0, -1e100); x != 1 {
+		t.Error("Expected", 1, "Got", x)
+	}
+
+	// 0.1 加一百萬次，單純相加的誤差會明顯大於補償版本
+	xs := make([]float64, 1_000_000)
+	for i := range xs {
+		xs[i] = 0.1
+	}
+	naive := math.Abs(Sum(xs...) - 100000)
+	comp := math.Abs(Compensated(xs...) - 100000)
+	if comp >= naive || comp > 1e-9 {
+		t.Error("naive error", naive, "compensated error", comp)
+	}
+}
+
+func ExampleChecked() {
+	s, err := Checked[int8](100, 27)
+	fmt.Println(s, err)
+	_, err = Checked[int8](100, 28)
+	fmt.Println(errors.Is(err, ErrOverflow))
+	// Output:
+	// 127 <nil>
+	// true
+}
+
+func ExampleSaturating() {
+	fmt.Println(Saturating[uint8](200, 100))
+	// Output:
+	// 255
+}
+
+func ExampleCompensated() {
+	fmt.Println(Sum(1e100, 1.0, -1e100))
+	fmt.Println(Compensated(1e100, 1.0, -1e100))
+	// Output:
+	// 0
+	// 1
+}
@@ -0,0 +1,11 @@
+package mymath
+
+import (
+	"testing"
+
+	"github.com/andyrestart9/animalPackage/247-table-tests/sumtest"
+)
+
+func TestSum(t *testing.T) {
+	sumtest.Run(t, Sum)
+}
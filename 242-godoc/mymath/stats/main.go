@@ -0,0 +1,139 @@
+// Package stats provides descriptive statistics for []int and []float64:
+// mean, median, mode, variance, standard deviation and percentiles.
+//
+// 所有函式都不會修改傳入的切片；空切片會回傳 *EmptyInputError。
+package stats
+
+import (
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/andyrestart9/animalPackage/242-godoc/mymath/gsum"
+)
+
+// Number is the element type accepted by every function in this package.
+type Number interface {
+	~int | ~float64
+}
+
+// EmptyInputError is returned when a statistic is asked for on an empty slice.
+type EmptyInputError struct {
+	Op string // 哪一個函式，例如 "Mean"
+}
+
+func (e *EmptyInputError) Error() string {
+	return fmt.Sprintf("stats: %s of empty input", e.Op)
+}
+
+// PercentileError is returned by Percentile when p is outside [0, 100].
+type PercentileError struct {
+	P float64
+}
+
+func (e *PercentileError) Error() string {
+	return fmt.Sprintf("stats: percentile %v out of range [0, 100]", e.P)
+}
+
+// Mean returns the arithmetic mean of xs.
+func Mean[T Number](xs []T) (float64, error) {
+	if len(xs) == 0 {
+		return 0, &EmptyInputError{"Mean"}
+	}
+	return mean(floats(xs)), nil
+}
+
+// Median returns the middle value of xs, or the mean of the two middle
+// values when len(xs) is even.
+func Median[T Number](xs []T) (float64, error) {
+	if len(xs) == 0 {
+		return 0, &EmptyInputError{"Median"}
+	}
+	return percentile(sorted(xs), 50), nil
+}
+
+// Mode returns the most frequent values of xs in ascending order.
+// 出現次數一樣多的值都會回傳，所以結果可能不只一個。
+func Mode[T Number](xs []T) ([]T, error) {
+	if len(xs) == 0 {
+		return nil, &EmptyInputError{"Mode"}
+	}
+	counts := make(map[T]int)
+	most := 0
+	for _, v := range xs {
+		counts[v]++
+		most = max(most, counts[v])
+	}
+	var modes []T
+	for v, n := range counts {
+		if n == most {
+			modes = append(modes, v)
+		}
+	}
+	slices.Sort(modes)
+	return modes, nil
+}
+
+// Variance returns the population variance of xs.
+func Variance[T Number](xs []T) (float64, error) {
+	if len(xs) == 0 {
+		return 0, &EmptyInputError{"Variance"}
+	}
+	fs := floats(xs)
+	m := mean(fs)
+	// 先算出和平均數的差的平方，再用補償加法加總
+	for i, v := range fs {
+		fs[i] = (v - m) * (v - m)
+	}
+	return gsum.Compensated(fs...) / float64(len(fs)), nil
+}
+
+// StdDev returns the population standard deviation of xs.
+func StdDev[T Number](xs []T) (float64, error) {
+	v, err := Variance(xs)
+	if err != nil {
+		return 0, &EmptyInputError{"StdDev"}
+	}
+	return math.Sqrt(v), nil
+}
+
+// Percentile returns the p-th percentile of xs, 0 <= p <= 100, using
+// linear interpolation between the closest ranks.
+// Percentile(xs, 50) is the same as Median(xs).
+func Percentile[T Number](xs []T, p float64) (float64, error) {
+	if len(xs) == 0 {
+		return 0, &EmptyInputError{"Percentile"}
+	}
+	if p < 0 || p > 100 || math.IsNaN(p) {
+		return 0, &PercentileError{p}
+	}
+	return percentile(sorted(xs), p), nil
+}
+
+// percentile 假設 fs 已經排序好而且不是空的
+func percentile(fs []float64, p float64) float64 {
+	// 位置從 0 到 len-1，落在兩個元素之間就按比例內插
+	pos := p / 100 * float64(len(fs)-1)
+	lo := int(math.Floor(pos))
+	hi := int(math.Ceil(pos))
+	return fs[lo] + (fs[hi]-fs[lo])*(pos-float64(lo))
+}
+
+func mean(fs []float64) float64 {
+	return gsum.Compensated(fs...) / float64(len(fs))
+}
+
+// floats 把 xs 複製成新的 []float64，呼叫端可以放心修改
+func floats[T Number](xs []T) []float64 {
+	fs := make([]float64, len(xs))
+	for i, v := range xs {
+		fs[i] = float64(v)
+	}
+	return fs
+}
+
+func sorted[T Number](xs []T) []float64 {
+	fs := floats(xs)
+	slices.Sort(fs)
+	return fs
+}
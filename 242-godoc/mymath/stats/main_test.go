@@ -0,0 +1,169 @@
+package stats
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	type test struct {
+		data     []float64
+		mean     float64
+		median   float64
+		variance float64
+	}
+
+	tests := []test{
+		{[]float64{5}, 5, 5, 0},
+		{[]float64{1, 2, 3, 4}, 2.5, 2.5, 1.25},
+		{[]float64{3, 1, 2}, 2, 2, 2.0 / 3},
+		{[]float64{2, 4, 4, 4, 5, 5, 7, 9}, 5, 4.5, 4},
+	}
+
+	for _, v := range tests {
+		if x, _ := Mean(v.data); !near(x, v.mean) {
+			t.Error("Mean", v.data, "Expected", v.mean, "Got", x)
+		}
+		if x, _ := Median(v.data); !near(x, v.median) {
+			t.Error("Median", v.data, "Expected", v.median, "Got", x)
+		}
+		if x, _ := Variance(v.data); !near(x, v.variance) {
+			t.Error("Variance", v.data, "Expected", v.variance, "Got", x)
+		}
+		if x, _ := StdDev(v.data); !near(x, math.Sqrt(v.variance)) {
+			t.Error("StdDev", v.data, "Expected", math.Sqrt(v.variance), "Got", x)
+		}
+	}
+}
+
+func TestInts(t *testing.T) {
+	xs := []int{2, 4, 4, 4, 5, 5, 7, 9}
+	if x, _ := Mean(xs); x != 5 {
+		t.Error("Expected", 5, "Got", x)
+	}
+	if x, _ := StdDev(xs); x != 2 {
+		t.Error("Expected", 2, "Got", x)
+	}
+	// 不能改到呼叫端的切片
+	if !slices.Equal(xs, []int{2, 4, 4, 4, 5, 5, 7, 9}) {
+		t.Error("input was modified:", xs)
+	}
+}
+
+func TestMode(t *testing.T) {
+	if x, _ := Mode([]int{1, 2, 2, 3}); !slices.Equal(x, []int{2}) {
+		t.Error("Expected", []int{2}, "Got", x)
+	}
+	if x, _ := Mode([]int{3, 1, 3, 1, 2}); !slices.Equal(x, []int{1, 3}) {
+		t.Error("Expected", []int{1, 3}, "Got", x)
+	}
+	if x, _ := Mode([]float64{0.5}); !slices.Equal(x, []float64{0.5}) {
+		t.Error("Expected", []float64{0.5}, "Got", x)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	xs := []int{40, 10, 30, 20, 50}
+	type test struct {
+		p      float64
+		answer float64
+	}
+	tests := []test{
+		{0, 10},
+		{25, 20},
+		{50, 30},
+		{90, 46},
+		{100, 50},
+	}
+	for _, v := range tests {
+		if x, err := Percentile(xs, v.p); err != nil || !near(x, v.answer) {
+			t.Error("p", v.p, "Expected", v.answer, "Got", x, err)
+		}
+	}
+
+	for _, p := range []float64{-1, 100.5, math.NaN()} {
+		_, err := Percentile(xs, p)
+		var pe *PercentileError
+		if !errors.As(err, &pe) {
+			t.Error("p", p, "Expected *PercentileError Got", err)
+		}
+	}
+}
+
+func TestEmpty(t *testing.T) {
+	var xs []int
+	_, errMean := Mean(xs)
+	_, errMedian := Median(xs)
+	_, errMode := Mode(xs)
+	_, errVariance := Variance(xs)
+	_, errStdDev := StdDev(xs)
+	_, errPercentile := Percentile(xs, 50)
+
+	errs := map[string]error{
+		"Mean":       errMean,
+		"Median":     errMedian,
+		"Mode":       errMode,
+		"Variance":   errVariance,
+		"StdDev":     errStdDev,
+		"Percentile": errPercentile,
+	}
+	for op, err := range errs {
+		var e *EmptyInputError
+		if !errors.As(err, &e) || e.Op != op {
+			t.Error(op, "Expected *EmptyInputError Got", err)
+		}
+	}
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+// ExampleMean 示範 Mean 的用法，[]int 和 []float64 都可以傳入
+func ExampleMean() {
+	fmt.Println(Mean([]int{1, 2, 3, 4}))
+	// Output:
+	// 2.5 <nil>
+}
+
+// ExampleMedian 示範偶數個元素時，Median 取中間兩個數的平均
+func ExampleMedian() {
+	fmt.Println(Median([]float64{7, 1, 3, 5}))
+	// Output:
+	// 4 <nil>
+}
+
+// ExampleMode 示範出現次數一樣多時，Mode 會回傳所有的眾數
+func ExampleMode() {
+	fmt.Println(Mode([]int{1, 3, 3, 2, 1}))
+	// Output:
+	// [1 3] <nil>
+}
+
+// ExampleStdDev 示範母體標準差
+func ExampleStdDev() {
+	fmt.Println(StdDev([]int{2, 4, 4, 4, 5, 5, 7, 9}))
+	// Output:
+	// 2 <nil>
+}
+
+// ExamplePercentile 示範第 90 百分位數，落在兩個元素之間時會線性內插
+func ExamplePercentile() {
+	fmt.Println(Percentile([]int{10, 20, 30, 40, 50}, 90))
+	// Output:
+	// 46 <nil>
+}
+
+// ExampleEmptyInputError 示範空切片會回傳 *EmptyInputError，可以用 errors.As 取出
+func ExampleEmptyInputError() {
+	_, err := Mean([]float64{})
+	var e *EmptyInputError
+	fmt.Println(errors.As(err, &e), e.Op)
+	fmt.Println(err)
+	// Output:
+	// true Mean
+	// stats: Mean of empty input
+}
@@ -8,6 +8,8 @@ package main
 
 import (
     "testing"
+
+    "github.com/andyrestart9/animalPackage/247-table-tests/sumtest"
 )
 
 // TestMySum 用來測試 mySum 函式是否能正確地將兩個整數相加
@@ -31,6 +33,12 @@ func TestMySum(t *testing.T) {
         t.Error("Expected", 5, "Got", x)
     }
 }
+
+// TestMySumTable 用 247-table-tests 共用的表格再測一次 mySum
+func TestMySumTable(t *testing.T) {
+    sumtest.Run(t, mySum)
+}
+
 /*
 為什麼 t.Error 用 “go to definition” 找到的是 func (c *common) Error(args ...any) ？
 
@@ -0,0 +1,152 @@
+// Package animal 是 246-002-embedding-and-promotion 的延伸：
+// 用一個基底型別 Animal 加上可以自由組合的行為 (mixin)：Walker、Swimmer、Flyer，
+// 具體的物種把它們匿名嵌入，需要的話再覆寫 (override) 被提升的方法。
+//
+// 注意 mixin 不知道自己被誰嵌入，所以 Walker.Walk 拿不到 Animal.Name；
+// 要用到名字的方法（例如 Move）只能由外層的物種自己實作。
+package animal
+
+import (
+	"fmt"
+	"strings"
+)
+
+// --------- 介面 ---------
+
+// Speaker is implemented by every species through Animal.
+type Speaker interface {
+	Speak() string
+}
+
+// Mover is implemented by every species through Animal.
+type Mover interface {
+	Move() string
+}
+
+// CanWalk is implemented by species that embed Walker.
+type CanWalk interface {
+	Walk() string
+}
+
+// CanSwim is implemented by species that embed Swimmer.
+type CanSwim interface {
+	Swim() string
+}
+
+// CanFly is implemented by species that embed Flyer.
+type CanFly interface {
+	Fly() string
+}
+
+// --------- 基底型別 ---------
+
+// Animal is the base type every species embeds.
+type Animal struct {
+	Name string
+}
+
+func (a Animal) Speak() string { return a.Name + " makes a sound" }
+
+func (a Animal) Move() string { return a.Name + " moves" }
+
+func (a Animal) Sleep() string { return a.Name + " is sleeping" }
+
+// Rename 用指標接收器，所以只在 *Dog、*Cat… 的方法集裡，Dog 值沒有
+func (a *Animal) Rename(name string) { a.Name = name }
+
+// --------- mixin ---------
+
+// Walker gives a species the ability to walk.
+type Walker struct {
+	Legs int
+}
+
+func (w Walker) Walk() string { return fmt.Sprintf("walks on %d legs", w.Legs) }
+
+// Swimmer gives a species the ability to swim.
+type Swimmer struct{}
+
+func (Swimmer) Swim() string { return "swims" }
+
+// Flyer gives a species the ability to fly.
+type Flyer struct{}
+
+func (Flyer) Fly() string { return "flies" }
+
+// --------- 物種 ---------
+
+// Dog walks and swims, and overrides Speak and Move.
+type Dog struct {
+	Animal
+	Walker
+	Swimmer
+}
+
+// NewDog returns a Dog with four legs.
+func NewDog(name string) Dog { return Dog{Animal{name}, Walker{4}, Swimmer{}} }
+
+func (d Dog) Speak() string { return d.Name + " says Woof" }
+
+func (d Dog) Move() string { return moves(d.Name, d.Walk(), d.Swim()) }
+
+// Cat walks and overrides only Speak; Move is promoted from Animal.
+type Cat struct {
+	Animal
+	Walker
+}
+
+// NewCat returns a Cat with four legs.
+func NewCat(name string) Cat { return Cat{Animal{name}, Walker{4}} }
+
+func (c Cat) Speak() string { return c.Name + " says Meow" }
+
+// Duck walks, swims and flies.
+type Duck struct {
+	Animal
+	Walker
+	Swimmer
+	Flyer
+}
+
+// NewDuck returns a Duck with two legs.
+func NewDuck(name string) Duck { return Duck{Animal{name}, Walker{2}, Swimmer{}, Flyer{}} }
+
+func (d Duck) Speak() string { return d.Name + " says Quack" }
+
+func (d Duck) Move() string { return moves(d.Name, d.Walk(), d.Swim(), d.Fly()) }
+
+// Fish only swims.
+type Fish struct {
+	Animal
+	Swimmer
+}
+
+// NewFish returns a Fish.
+func NewFish(name string) Fish { return Fish{Animal{name}, Swimmer{}} }
+
+func (f Fish) Move() string { return moves(f.Name, f.Swim()) }
+
+// Penguin walks and swims but cannot fly, so it has no Flyer.
+type Penguin struct {
+	Animal
+	Walker
+	Swimmer
+}
+
+// NewPenguin returns a Penguin with two legs.
+func NewPenguin(name string) Penguin { return Penguin{Animal{name}, Walker{2}, Swimmer{}} }
+
+func (p Penguin) Speak() string { return p.Name + " says Squawk" }
+
+func (p Penguin) Move() string { return moves(p.Name, p.Walk(), p.Swim()) }
+
+// moves 把 "walks", "swims", "flies" 接成 "Donald walks, swims and flies"
+func moves(name string, ways ...string) string {
+	switch len(ways) {
+	case 0:
+		return name + " moves"
+	case 1:
+		return name + " " + ways[0]
+	}
+	return name + " " + strings.Join(ways[:len(ways)-1], ", ") + " and " + ways[len(ways)-1]
+}
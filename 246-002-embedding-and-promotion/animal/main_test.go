@@ -0,0 +1,181 @@
+package animal
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// 編譯期檢查每個物種擁有哪些能力
+var (
+	_ interface {
+		Speaker
+		Mover
+		CanWalk
+		CanSwim
+	} = Dog{}
+	_ interface {
+		Speaker
+		Mover
+		CanWalk
+		CanSwim
+		CanFly
+	} = Duck{}
+	_ CanSwim = Fish{}
+)
+
+func TestBehaviour(t *testing.T) {
+	type test struct {
+		a interface {
+			Speaker
+			Mover
+		}
+		speak string
+		move  string
+	}
+	tests := []test{
+		{NewDog("Buddy"), "Buddy says Woof", "Buddy walks on 4 legs and swims"},
+		{NewCat("Kitty"), "Kitty says Meow", "Kitty moves"},
+		{NewDuck("Donald"), "Donald says Quack", "Donald walks on 2 legs, swims and flies"},
+		{NewFish("Nemo"), "Nemo makes a sound", "Nemo swims"},
+		{NewPenguin("Pingu"), "Pingu says Squawk", "Pingu walks on 2 legs and swims"},
+	}
+	for _, v := range tests {
+		if x := v.a.Speak(); x != v.speak {
+			t.Error("Expected", v.speak, "Got", x)
+		}
+		if x := v.a.Move(); x != v.move {
+			t.Error("Expected", v.move, "Got", x)
+		}
+	}
+
+	// 覆寫後，被遮蔽的方法還是可以透過欄位名稱叫到
+	d := NewDog("Buddy")
+	if x := d.Animal.Speak(); x != "Buddy makes a sound" {
+		t.Error("Expected", "Buddy makes a sound", "Got", x)
+	}
+}
+
+func TestCapabilities(t *testing.T) {
+	type test struct {
+		a    any
+		walk bool
+		swim bool
+		fly  bool
+	}
+	tests := []test{
+		{NewDog("Buddy"), true, true, false},
+		{NewCat("Kitty"), true, false, false},
+		{NewDuck("Donald"), true, true, true},
+		{NewFish("Nemo"), false, true, false},
+		{NewPenguin("Pingu"), true, true, false},
+	}
+	for _, v := range tests {
+		_, walk := v.a.(CanWalk)
+		_, swim := v.a.(CanSwim)
+		_, fly := v.a.(CanFly)
+		if walk != v.walk || swim != v.swim || fly != v.fly {
+			t.Errorf("%T: Expected walk=%v swim=%v fly=%v Got %v %v %v", v.a, v.walk, v.swim, v.fly, walk, swim, fly)
+		}
+	}
+}
+
+func TestRenameNeedsPointer(t *testing.T) {
+	type renamer interface{ Rename(string) }
+	if _, ok := any(NewDog("Buddy")).(renamer); ok {
+		t.Error("Dog value should not have Rename in its method set")
+	}
+	d := NewDog("Buddy")
+	var r renamer = &d
+	r.Rename("Max")
+	if d.Name != "Max" {
+		t.Error("Expected", "Max", "Got", d.Name)
+	}
+}
+
+func TestExplain(t *testing.T) {
+	animal := reflect.TypeFor[Animal]()
+	dog := reflect.TypeFor[Dog]()
+
+	got := make(map[string]Method)
+	for _, m := range Explain(NewDog("Buddy")) {
+		got[m.Name] = m
+	}
+
+	type test struct {
+		name        string
+		declaredOn  reflect.Type
+		via         string
+		shadows     []reflect.Type
+		pointerOnly bool
+	}
+	tests := []test{
+		{"Move", dog, "", []reflect.Type{animal}, false},
+		{"Rename", animal, "Animal", nil, true},
+		{"Sleep", animal, "Animal", nil, false},
+		{"Speak", dog, "", []reflect.Type{animal}, false},
+		{"Swim", reflect.TypeFor[Swimmer](), "Swimmer", nil, false},
+		{"Walk", reflect.TypeFor[Walker](), "Walker", nil, false},
+	}
+	if len(got) != len(tests) {
+		t.Error("Expected", len(tests), "methods Got", Explain(NewDog("Buddy")))
+	}
+	for _, v := range tests {
+		m, ok := got[v.name]
+		if !ok {
+			t.Error("missing method", v.name)
+			continue
+		}
+		if m.DeclaredOn != v.declaredOn || strings.Join(m.Via, ".") != v.via ||
+			!reflect.DeepEqual(m.Shadows, v.shadows) || m.PointerOnly != v.pointerOnly {
+			t.Errorf("%s: Expected %v via %q shadows %v pointerOnly %v Got %+v",
+				v.name, v.declaredOn, v.via, v.shadows, v.pointerOnly, m)
+		}
+		if m.Promoted() != (v.via != "") || m.Overrides() != (len(v.shadows) > 0) {
+			t.Errorf("%s: Promoted/Overrides disagree with %+v", v.name, m)
+		}
+	}
+}
+
+// 多層嵌入：Puppy 嵌入 Dog，Speak 從 Dog 提升，並遮蔽更深一層的 Animal.Speak
+type Puppy struct {
+	Dog
+}
+
+// 命名欄位：跟 main.go 的 Cat 一樣，方法不會被提升
+type Statue struct {
+	Model Animal
+}
+
+func TestExplainDeep(t *testing.T) {
+	for _, m := range Explain(&Puppy{NewDog("Rex")}) {
+		if m.Name != "Speak" {
+			continue
+		}
+		if m.DeclaredOn != reflect.TypeFor[Dog]() || strings.Join(m.Via, ".") != "Dog" {
+			t.Error("Expected Speak from Dog Got", m)
+		}
+		if !reflect.DeepEqual(m.Shadows, []reflect.Type{reflect.TypeFor[Animal]()}) {
+			t.Error("Expected Speak to shadow Animal Got", m.Shadows)
+		}
+	}
+
+	if ms := Explain(Statue{}); len(ms) != 0 {
+		t.Error("Expected no methods Got", ms)
+	}
+	if ms := Explain(nil); ms != nil {
+		t.Error("Expected nil Got", ms)
+	}
+}
+
+func ExampleReport() {
+	fmt.Print(Report(NewCat("Kitty")))
+	// Output:
+	// animal.Cat:
+	//   Move promoted from animal.Animal via Animal
+	//   Rename promoted from animal.Animal via Animal (pointer receiver)
+	//   Sleep promoted from animal.Animal via Animal
+	//   Speak declared on animal.Cat, overrides animal.Animal
+	//   Walk promoted from animal.Walker via Walker
+}
@@ -0,0 +1,177 @@
+package animal
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// Method describes where a method in a type's method set comes from.
+type Method struct {
+	Name string
+	// DeclaredOn is the type whose source declares the method.
+	DeclaredOn reflect.Type
+	// Via lists the embedded field names walked from the outer type to
+	// DeclaredOn. It is empty when the outer type declares the method itself.
+	Via []string
+	// Shadows lists embedded types that also declare the method but lose
+	// to DeclaredOn, i.e. the methods it overrides.
+	Shadows []reflect.Type
+	// PointerOnly is true when the method is only in the method set of *T.
+	PointerOnly bool
+}
+
+// Promoted reports whether the method comes from an embedded field.
+func (m Method) Promoted() bool { return len(m.Via) > 0 }
+
+// Overrides reports whether the method hides a method of an embedded type.
+func (m Method) Overrides() bool { return len(m.Shadows) > 0 }
+
+func (m Method) String() string {
+	var b strings.Builder
+	b.WriteString(m.Name)
+	if m.Promoted() {
+		fmt.Fprintf(&b, " promoted from %v via %s", m.DeclaredOn, strings.Join(m.Via, "."))
+	} else {
+		fmt.Fprintf(&b, " declared on %v", m.DeclaredOn)
+	}
+	if m.Overrides() {
+		names := make([]string, len(m.Shadows))
+		for i, t := range m.Shadows {
+			names[i] = t.String()
+		}
+		fmt.Fprintf(&b, ", overrides %s", strings.Join(names, ", "))
+	}
+	if m.PointerOnly {
+		b.WriteString(" (pointer receiver)")
+	}
+	return b.String()
+}
+
+// Explain returns, for every exported method of v's type and its pointer
+// type, where the method is declared and what it overrides. v may be a
+// value or a pointer; the result is sorted by method name.
+//
+// reflect 本身分不出方法是自己宣告的還是被提升的，所以這裡看的是方法的原始碼位置：
+// 編譯器替被提升的方法產生的包裝函式 (wrapper)，位置是 "<autogenerated>"。
+// 未匯出的方法不在 reflect 的方法集裡，所以不會出現在結果中。
+func Explain(v any) []Method {
+	t := reflect.TypeOf(v)
+	if t == nil {
+		return nil
+	}
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return ExplainType(t)
+}
+
+// ExplainType is like Explain but takes the type directly.
+func ExplainType(t reflect.Type) []Method {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	all := t
+	if t.Kind() != reflect.Interface {
+		all = reflect.PointerTo(t)
+	}
+
+	methods := make([]Method, 0, all.NumMethod())
+	for i := range all.NumMethod() {
+		name := all.Method(i).Name
+		m := Method{Name: name}
+		if _, ok := t.MethodByName(name); !ok {
+			m.PointerOnly = true
+		}
+
+		if declares(t, name) {
+			m.DeclaredOn = t
+			for _, e := range embeddedDeclaring(t, name) {
+				m.Shadows = append(m.Shadows, e.typ)
+			}
+		} else {
+			found := embeddedDeclaring(t, name)
+			if len(found) > 0 {
+				// 最淺的那個贏，其餘的被它遮蔽
+				m.DeclaredOn, m.Via = found[0].typ, found[0].path
+				for _, e := range found[1:] {
+					m.Shadows = append(m.Shadows, e.typ)
+				}
+			}
+		}
+		methods = append(methods, m)
+	}
+	return methods
+}
+
+// Report returns Explain(v) as one line per method, for printing.
+func Report(v any) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%v:\n", reflect.TypeOf(v))
+	for _, m := range Explain(v) {
+		fmt.Fprintf(&b, "  %v\n", m)
+	}
+	return b.String()
+}
+
+type embedded struct {
+	typ  reflect.Type
+	path []string
+}
+
+// embeddedDeclaring 以廣度優先走訪 t 的嵌入欄位，依深度由淺到深
+// 回傳所有自己宣告了 name 方法的嵌入型別
+func embeddedDeclaring(t reflect.Type, name string) []embedded {
+	var found []embedded
+	queue := []embedded{{typ: t}}
+	seen := map[reflect.Type]bool{t: true}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		if cur.typ.Kind() != reflect.Struct {
+			continue
+		}
+		for i := range cur.typ.NumField() {
+			f := cur.typ.Field(i)
+			if !f.Anonymous {
+				continue // 命名欄位（像 Cat 的 Ani Animal）的方法不會被提升
+			}
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if seen[ft] {
+				continue
+			}
+			seen[ft] = true
+			e := embedded{typ: ft, path: append(append([]string(nil), cur.path...), f.Name)}
+			if declares(ft, name) {
+				found = append(found, e)
+			}
+			queue = append(queue, e)
+		}
+	}
+	return found
+}
+
+// declares 判斷 t 的原始碼是否自己宣告了 name 方法
+func declares(t reflect.Type, name string) bool {
+	if t.Name() == "" {
+		return false
+	}
+	if t.Kind() == reflect.Interface {
+		_, ok := t.MethodByName(name)
+		return ok
+	}
+	// 值接收器的方法要看 T 的方法集，*T 裡的那一份是編譯器產生的包裝函式
+	m, ok := t.MethodByName(name)
+	if !ok {
+		if m, ok = reflect.PointerTo(t).MethodByName(name); !ok {
+			return false
+		}
+	}
+	pc := m.Func.Pointer()
+	file, _ := runtime.FuncForPC(pc).FileLine(pc)
+	return file != "<autogenerated>"
+}
@@ -1,6 +1,10 @@
 package main
 
-import "fmt"
+import (
+    "fmt"
+
+    "github.com/andyrestart9/animalPackage/246-002-embedding-and-promotion/animal"
+)
 
 // 定義一個基底型別 Animal，帶一個方法 Speak
 type Animal struct {
@@ -50,4 +54,14 @@ func main() {
 
     // 正確的呼叫方式，要透過命名欄位 Ani：
     c.Ani.Speak() // 輸出：Kitty makes a sound
+
+    // 用 animal.Report 檢查哪些方法被提升、從哪裡提升：
+    // Dog 會列出 Speak promoted from main.Animal via Animal (pointer receiver)
+    // Cat 因為是命名欄位，什麼方法都沒有
+    fmt.Print(animal.Report(d))
+    fmt.Print(animal.Report(c))
+
+    // animal 套件裡的物種用 mixin 組合行為，並覆寫被提升的方法
+    fmt.Print(animal.Report(animal.NewDuck("Donald")))
+    fmt.Println(animal.NewDuck("Donald").Move())
 }
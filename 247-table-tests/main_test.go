@@ -2,27 +2,11 @@ package main
 
 import (
 	"testing"
+
+	"github.com/andyrestart9/animalPackage/247-table-tests/sumtest"
 )
 
+// 表格本身放在 sumtest 套件，其他 Sum 實作的測試也共用同一張表
 func TestMySum(t *testing.T) {
-
-	type test struct {
-		data   []int
-		answer int
-	}
-
-	tests := []test{
-		{[]int{21, 21}, 42},
-		{[]int{3, 4, 5}, 12},
-		{[]int{1, 1}, 2},
-		{[]int{-1, 0, 1}, 0},
-	}
-
-	for _, v := range tests {
-		x := mySum(v.data...)
-		if x != v.answer {
-			t.Error("Expected", v.answer, "Got", x)
-		}
-	}
-
+	sumtest.Run(t, mySum)
 }
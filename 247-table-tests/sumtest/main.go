@@ -0,0 +1,35 @@
+// Package sumtest 把 TestMySum 的表格抽出來，讓這個 module 裡所有的 Sum 實作
+// （mySum、mymath.Sum、acdc.Sum、gsum 的各種模式）都用同一張表測試。
+package sumtest
+
+import "testing"
+
+// Test is one row of the shared table.
+type Test struct {
+	Data   []int
+	Answer int
+}
+
+// Tests is the table every Sum implementation must pass.
+var Tests = []Test{
+	{[]int{21, 21}, 42},
+	{[]int{3, 4, 5}, 12},
+	{[]int{1, 1}, 2},
+	{[]int{-1, 0, 1}, 0},
+	{[]int{}, 0},
+	{[]int{7}, 7},
+	{[]int{-5, -6}, -11},
+}
+
+// Run checks sum against every row of Tests.
+//
+// 跟原本的 TestMySum 一樣用 t.Error，一筆錯了還會繼續跑下一筆。
+func Run(t testing.TB, sum func(xi ...int) int) {
+	t.Helper()
+	for _, v := range Tests {
+		x := sum(v.Data...)
+		if x != v.Answer {
+			t.Error("Data", v.Data, "Expected", v.Answer, "Got", x)
+		}
+	}
+}
@@ -1,6 +1,16 @@
 package acdc
 
-import "fmt"
+import (
+	"fmt"
+	"testing"
+
+	"github.com/andyrestart9/animalPackage/247-table-tests/sumtest"
+)
+
+// TestSum 用 247-table-tests 共用的表格檢查 Sum
+func TestSum(t *testing.T) {
+	sumtest.Run(t, Sum)
+}
 
 // ExampleSum 定义了一个针对 Sum 函数的示例。
 // Go 测试框架会把所有名称以 Example 为前缀的函数当作“示例测试”来运行。
@@ -8,12 +18,12 @@ import "fmt"
 func ExampleSum() {
 	// 调用 acdc 包里的 Sum 函数，并把结果打印到标准输出
 	fmt.Println(Sum(2, 3))
+	// 下面的注释告诉 go test：运行 ExampleSum 时，
+	// 捕获到的标准输出（println 的内容）必须**精确**匹配 Output 后面的文本（这里是 “5”），
+	// 否则该示例测试会被判定为失败。
+	// 所以这段说明要写在 Output 上面，写在下面会被当成期望输出的一部分。
 	// Output:
 	// 5
-	//
-	// 上面的注释告诉 go test：运行 ExampleSum 时，
-	// 捕获到的标准输出（println 的内容）必须**精确**匹配下面注释里的文本（这里是 “5”），
-	// 否则该示例测试会被判定为失败。
 }
 
 // ExampleSum 定义了一个针对 Sum 函数的示例。
@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/andyrestart9/animalPackage/252-benchmark/mystr"
@@ -18,4 +19,12 @@ func main() {
 
 	fmt.Printf("\n%s\n", mystr.Cat(xs))
 	fmt.Printf("\n%s\n", mystr.Join(xs))
+
+	j := mystr.Joiner{Sep: ", ", Last: " and ", Prefix: "[", Suffix: "]"}
+	fmt.Printf("\n%s\n", j.Join(xs[:5]))
+
+	// JoinTo 直接寫到 os.Stdout，不會先在記憶體裡組出整個字串
+	fmt.Println()
+	j.JoinTo(os.Stdout, xs)
+	fmt.Println()
 }
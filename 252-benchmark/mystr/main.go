@@ -1,8 +1,19 @@
+// Package mystr 提供把字符串切片拼接成一个字符串的工具，可以自定义分隔符、前后缀，以及最后一个分隔符。
 package mystr
 
-import "strings"
+import (
+	"io"
+	"iter"
+	"slices"
+	"strings"
+)
 
+// Cat joins xs with a single space using += concatenation.
+// 空切片或 nil 返回空字符串，而不是 panic。
 func Cat(xs []string) string {
+	if len(xs) == 0 {
+		return ""
+	}
 	s := xs[0]
 	for _, v := range xs[1:] {
 		s += " "
@@ -11,9 +22,128 @@ func Cat(xs []string) string {
 	return s
 }
 
+// Join joins xs with a single space using strings.Join.
 func Join(xs []string) string {
 	return strings.Join(xs, " ")
 }
+
+// JoinSep joins xs with sep.
+func JoinSep(xs []string, sep string) string {
+	return Joiner{Sep: sep}.Join(xs)
+}
+
+// Joiner describes how a slice of strings is joined.
+//
+// 零值 Joiner 等同于 strings.Join(xs, "")。
+type Joiner struct {
+	Sep    string // 元素之间的分隔符
+	Last   string // 最后两个元素之间的分隔符，例如 " and "；空字符串表示沿用 Sep
+	Prefix string // 整个结果的前缀
+	Suffix string // 整个结果的后缀
+}
+
+// lastSep 返回最后两个元素之间要用的分隔符
+func (j Joiner) lastSep() string {
+	if j.Last != "" {
+		return j.Last
+	}
+	return j.Sep
+}
+
+// Join joins xs according to j. It returns "" for nil or empty xs,
+// without Prefix or Suffix.
+func (j Joiner) Join(xs []string) string {
+	if len(xs) == 0 {
+		return ""
+	}
+
+	// 跟 strings.Join 一样，先算出总长度，一次 Grow 到位，只分配一次内存
+	n := len(j.Prefix) + len(j.Suffix)
+	if len(xs) > 1 {
+		n += len(j.Sep)*(len(xs)-2) + len(j.lastSep())
+	}
+	for _, v := range xs {
+		n += len(v)
+	}
+
+	var b strings.Builder
+	b.Grow(n)
+	b.WriteString(j.Prefix)
+	last := len(xs) - 1
+	for i, v := range xs {
+		switch {
+		case i == 0:
+		case i == last:
+			b.WriteString(j.lastSep())
+		default:
+			b.WriteString(j.Sep)
+		}
+		b.WriteString(v)
+	}
+	b.WriteString(j.Suffix)
+	return b.String()
+}
+
+// JoinTo writes xs joined according to j to w and returns the number of
+// bytes written. The joined string is never built in memory.
+func (j Joiner) JoinTo(w io.Writer, xs []string) (int64, error) {
+	return j.WriteSeq(w, slices.Values(xs))
+}
+
+// WriteSeq is like JoinTo but reads the elements from seq, so the input
+// does not have to be a slice either. Nothing is written for an empty seq.
+func (j Joiner) WriteSeq(w io.Writer, seq iter.Seq[string]) (int64, error) {
+	var n int64
+	write := func(s string) error {
+		if s == "" {
+			return nil
+		}
+		m, err := io.WriteString(w, s)
+		n += int64(m)
+		return err
+	}
+
+	// 因为要知道哪一个是最后一个元素才能决定用 Sep 还是 Last，
+	// 所以手上永远先保留一个还没写出去的元素 (pending)
+	var (
+		pending string
+		count   int
+		err     error
+	)
+	for v := range seq {
+		switch count {
+		case 0:
+			err = write(j.Prefix)
+		case 1:
+			err = write(pending)
+		default:
+			if err = write(j.Sep); err == nil {
+				err = write(pending)
+			}
+		}
+		if err != nil {
+			return n, err
+		}
+		pending = v
+		count++
+	}
+
+	switch count {
+	case 0:
+		return 0, nil
+	case 1:
+		err = write(pending)
+	default:
+		if err = write(j.lastSep()); err == nil {
+			err = write(pending)
+		}
+	}
+	if err == nil {
+		err = write(j.Suffix)
+	}
+	return n, err
+}
+
 /*
 Cat VS strings.Join
 
@@ -32,4 +162,4 @@ Join 先计算出总长度 n，一次性通过 b.Grow(n) 预分配缓冲区，
 2. 内存分配
 Cat 在循环里每次增长都会触发新的分配和旧数据拷贝，分配次数 ≈ 元素个数。
 Join 只调用一次 Grow，只分配一次大块内存，大大减少 GC 压力和分配开销。
-*/
\ No newline at end of file
+*/
@@ -1,7 +1,10 @@
 package mystr
 
 import (
+	"bytes"   // 用于接收 JoinTo 写出的内容
+	"errors"  // 用于构造写入失败时的错误
 	"fmt"     // 用于格式化输出，Example 函数中调用 fmt.Println 输出结果
+	"io"      // 用于 io.Discard，基准测试里丢弃 JoinTo 的输出
 	"strings" // 用于拆分字符串，测试和基准测试里使用 strings.Split
 	"testing" // Go 内置测试框架，用于编写单元测试和基准测试
 )
@@ -26,6 +29,97 @@ func TestJoin(t *testing.T) {
 	}
 }
 
+// TestCatEmpty 验证 Cat 遇到 nil 或空切片时返回空字符串而不是 panic
+func TestCatEmpty(t *testing.T) {
+	if s := Cat(nil); s != "" {
+		t.Error("got", s, "want", "")
+	}
+	if s := Cat([]string{}); s != "" {
+		t.Error("got", s, "want", "")
+	}
+}
+
+// joinTests 是 Joiner.Join 和 Joiner.JoinTo 共用的表格
+var joinTests = []struct {
+	j      Joiner
+	data   []string
+	answer string
+}{
+	{Joiner{Sep: ", "}, nil, ""},
+	{Joiner{Sep: ", ", Prefix: "[", Suffix: "]"}, []string{}, ""},
+	{Joiner{Sep: ", "}, []string{"a"}, "a"},
+	{Joiner{Sep: ", "}, []string{"a", "b", "c"}, "a, b, c"},
+	{Joiner{Sep: ", ", Last: " and "}, []string{"a"}, "a"},
+	{Joiner{Sep: ", ", Last: " and "}, []string{"a", "b"}, "a and b"},
+	{Joiner{Sep: ", ", Last: " and "}, []string{"a", "b", "c"}, "a, b and c"},
+	{Joiner{Sep: ",", Prefix: "[", Suffix: "]"}, []string{"1", "2", "3"}, "[1,2,3]"},
+	{Joiner{Prefix: "<", Suffix: ">"}, []string{"x"}, "<x>"},
+	{Joiner{}, []string{"a", "b"}, "ab"},
+	{Joiner{Sep: " "}, []string{"", "b", ""}, " b "},
+}
+
+// TestJoiner 验证 Joiner.Join 的分隔符、前后缀和最后分隔符
+func TestJoiner(t *testing.T) {
+	for _, v := range joinTests {
+		s := v.j.Join(v.data)
+		if s != v.answer {
+			t.Errorf("%+v.Join(%q): got %q want %q", v.j, v.data, s, v.answer)
+		}
+	}
+}
+
+// TestJoinTo 验证串流版本写出的内容和字节数与 Join 一致
+func TestJoinTo(t *testing.T) {
+	for _, v := range joinTests {
+		var buf bytes.Buffer
+		n, err := v.j.JoinTo(&buf, v.data)
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+		if buf.String() != v.answer || n != int64(len(v.answer)) {
+			t.Errorf("%+v.JoinTo(%q): got %q (%d bytes) want %q", v.j, v.data, buf.String(), n, v.answer)
+		}
+	}
+}
+
+// TestJoinSep 验证 JoinSep 只是 Joiner{Sep: sep} 的简写
+func TestJoinSep(t *testing.T) {
+	if s := JoinSep([]string{"a", "b", "c"}, "-"); s != "a-b-c" {
+		t.Error("got", s, "want", "a-b-c")
+	}
+}
+
+// failWriter 在写出 limit 个字节后就返回错误，用来模拟写入失败
+type failWriter struct {
+	limit int
+	n     int
+}
+
+var errWrite = errors.New("write failed")
+
+func (w *failWriter) Write(p []byte) (int, error) {
+	if w.n+len(p) > w.limit {
+		m := w.limit - w.n
+		w.n = w.limit
+		return m, errWrite
+	}
+	w.n += len(p)
+	return len(p), nil
+}
+
+// TestJoinToError 验证写入失败时 JoinTo 会停下来并返回错误和已写出的字节数
+func TestJoinToError(t *testing.T) {
+	j := Joiner{Sep: ", ", Prefix: "[", Suffix: "]"}
+	w := &failWriter{limit: 4}
+	n, err := j.JoinTo(w, []string{"aa", "bb", "cc"})
+	if !errors.Is(err, errWrite) {
+		t.Fatal("got", err, "want", errWrite)
+	}
+	if n != 4 {
+		t.Error("got", n, "bytes want", 4)
+	}
+}
+
 // ExampleCat 用于文档测试，示例 Cat 函数的典型用法，并验证输出
 func ExampleCat() {
 	s := "Shaken not stirred"   // 原始字符串
@@ -44,6 +138,14 @@ func ExampleJoin() {
 	// Shaken not stirred
 }
 
+// ExampleJoiner 示例用 Last 拼出 "a, b and c" 这种句子
+func ExampleJoiner() {
+	j := Joiner{Sep: ", ", Last: " and ", Prefix: "I like ", Suffix: "."}
+	fmt.Println(j.Join([]string{"apples", "pears", "plums"}))
+	// Output:
+	// I like apples, pears and plums.
+}
+
 // 用于基准测试的长文本常量，模拟真实场景下更大的输入规模
 const s = "We ask ourselves, Who am I to be brilliant, gorgeous, talented, fabulous? Actually, who are you not to be? Your playing small does not serve the world. There is nothing enlightened about shrinking so that other people won't feel insecure around you. We are all meant to shine, as children do. We were born to make manifest the glory that is within us. It's not just in some of us; it's in everyone. And as we let our own light shine, we unconsciously give other people permission to do the same. As we are liberated from our own fear, our presence automatically liberates others. - Marianne Williamson"
 
@@ -78,6 +180,49 @@ func BenchmarkJoin(b *testing.B) {
 		Join(xs) // 重复调用 Join 函数
 	}
 }
+
+// BenchmarkMatrix 把所有拼接方式 x 不同输入规模排成矩阵，用 b.Run 产生子基准：
+//
+//	go test -bench=Matrix -benchmem
+//
+// 报告里的名字会是 BenchmarkMatrix/Cat/words=1000-8 这种形式，方便横向比较
+func BenchmarkMatrix(b *testing.B) {
+	words := strings.Split(s, " ")
+	variants := []struct {
+		name string
+		f    func(xs []string)
+	}{
+		{"Cat", func(xs []string) { Cat(xs) }},
+		{"Join", func(xs []string) { Join(xs) }},
+		{"JoinSep", func(xs []string) { JoinSep(xs, ", ") }},
+		{"Joiner", func(xs []string) {
+			Joiner{Sep: ", ", Last: " and ", Prefix: "[", Suffix: "]"}.Join(xs)
+		}},
+		{"JoinTo", func(xs []string) {
+			Joiner{Sep: ", ", Last: " and ", Prefix: "[", Suffix: "]"}.JoinTo(io.Discard, xs)
+		}},
+	}
+	for _, size := range []int{10, 100, 1000, 10000} {
+		// 重复句子里的单字，凑出指定长度的切片
+		in := make([]string, size)
+		for i := range in {
+			in[i] = words[i%len(words)]
+		}
+		for _, v := range variants {
+			// Cat 是 O(n²)，10000 个元素时太慢，就不跑了
+			if v.name == "Cat" && size > 1000 {
+				continue
+			}
+			b.Run(fmt.Sprintf("%s/words=%d", v.name, size), func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					v.f(in)
+				}
+			})
+		}
+	}
+}
+
 /*
 基准测试后的报告
 goos: darwin // 操作系统
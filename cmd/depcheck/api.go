@@ -0,0 +1,234 @@
+package main
+
+import (
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// uses 記錄我們的程式用到了哪些依賴的識別字：import 路徑 -> 識別字 -> 用到它的套件目錄
+type uses map[string]map[string][]string
+
+// collectUses 走過 root 底下所有的 .go 檔（包含 _test.go 和有 build tag 的檔案），
+// 找出 isDep 為 true 的 import 被用到的 pkg.Ident。
+// pkgName 回傳 import 路徑真正的套件名稱，給沒有取別名的 import 用。
+func collectUses(root string, isDep func(importPath string) bool, pkgName func(importPath string) string) (uses, error) {
+	u := uses{}
+	fset := token.NewFileSet()
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if p != root && (strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || name == "testdata" || name == "vendor") {
+				return filepath.SkipDir
+			}
+			// 巢狀的模組不算我們的程式
+			if _, err := os.Stat(filepath.Join(p, "go.mod")); p != root && err == nil {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(p, ".go") {
+			return nil
+		}
+
+		// 需要物件解析：被區域變數遮住的套件名稱 Obj 不是 nil
+		f, err := parser.ParseFile(fset, p, nil, 0)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, filepath.Dir(p))
+		if err != nil {
+			return err
+		}
+		fileUses(f, filepath.ToSlash(rel), isDep, pkgName, u)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, idents := range u {
+		for id, users := range idents {
+			slices.Sort(users)
+			idents[id] = slices.Compact(users)
+		}
+	}
+	return u, nil
+}
+
+// fileUses 把 f 裡 pkg.Ident 形式的選擇子記到 u
+func fileUses(f *ast.File, dir string, isDep func(string) bool, pkgName func(string) string, u uses) {
+	local := map[string]string{} // 檔案裡的套件名稱 -> import 路徑
+	for _, spec := range f.Imports {
+		ip, err := strconv.Unquote(spec.Path.Value)
+		if err != nil || !isDep(ip) {
+			continue
+		}
+		name := pkgName(ip)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		if name == "_" || name == "." {
+			continue // 點匯入不常見，這裡不處理
+		}
+		local[name] = ip
+	}
+	if len(local) == 0 {
+		return
+	}
+
+	ast.Inspect(f, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		id, ok := sel.X.(*ast.Ident)
+		if !ok || id.Obj != nil { // Obj 不是 nil 表示是區域的變數、參數或型別
+			return true
+		}
+		ip, ok := local[id.Name]
+		if !ok {
+			return true
+		}
+		if u[ip] == nil {
+			u[ip] = map[string][]string{}
+		}
+		u[ip][sel.Sel.Name] = append(u[ip][sel.Sel.Name], dir)
+		return true
+	})
+}
+
+// api 是一個套件匯出的套件層級識別字 -> 簽名
+type api struct {
+	Name  string // 套件名稱
+	Decls map[string]string
+}
+
+// loadAPI 解析 fsys 裡 dir 目錄的套件（不含 _test.go），
+// 用 build.Context 依 GOOS、GOARCH 和 tags 過濾檔案
+func loadAPI(fsys fs.FS, dir string, tags []string) (*api, error) {
+	ctxt := build.Default
+	ctxt.BuildTags = tags
+	ctxt.JoinPath = path.Join
+	ctxt.OpenFile = func(p string) (io.ReadCloser, error) { return fsys.Open(p) }
+
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+	a := &api{Decls: map[string]string{}}
+	fset := token.NewFileSet()
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		if ok, err := ctxt.MatchFile(dir, name); err != nil || !ok {
+			continue
+		}
+		src, err := fs.ReadFile(fsys, path.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		f, err := parser.ParseFile(fset, path.Join(dir, name), src, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		a.Name = f.Name.Name
+		for _, decl := range f.Decls {
+			declSigs(decl, a.Decls)
+		}
+	}
+	if a.Name == "" {
+		return nil, fs.ErrNotExist
+	}
+	return a, nil
+}
+
+// declSigs 把 decl 裡匯出的套件層級識別字和它的簽名寫進 sigs
+func declSigs(decl ast.Decl, sigs map[string]string) {
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		if d.Recv == nil && d.Name.IsExported() {
+			sigs[d.Name.Name] = "func" + funcSig(d.Type)
+		}
+	case *ast.GenDecl:
+		for _, spec := range d.Specs {
+			switch s := spec.(type) {
+			case *ast.TypeSpec:
+				if !s.Name.IsExported() {
+					continue
+				}
+				sig := "type "
+				if s.TypeParams != nil {
+					sig += "[" + fieldTypes(s.TypeParams, true) + "] "
+				}
+				if s.Assign.IsValid() {
+					sig += "= "
+				}
+				sigs[s.Name.Name] = sig + types.ExprString(s.Type)
+			case *ast.ValueSpec:
+				kind := d.Tok.String() // var 或 const
+				for _, id := range s.Names {
+					if !id.IsExported() {
+						continue
+					}
+					if s.Type != nil {
+						sigs[id.Name] = kind + " " + types.ExprString(s.Type)
+					} else {
+						// 沒寫型別就看不出來，只能比較種類
+						sigs[id.Name] = kind
+					}
+				}
+			}
+		}
+	}
+}
+
+// funcSig 回傳不含參數名稱的簽名，例如 "(int, string) error"，
+// 只改參數名稱不算簽名改變
+func funcSig(ft *ast.FuncType) string {
+	var b strings.Builder
+	if ft.TypeParams != nil {
+		b.WriteString("[" + fieldTypes(ft.TypeParams, true) + "]")
+	}
+	b.WriteString("(" + fieldTypes(ft.Params, false) + ")")
+	if ft.Results != nil {
+		res := fieldTypes(ft.Results, false)
+		if len(ft.Results.List) == 1 && len(ft.Results.List[0].Names) <= 1 {
+			b.WriteString(" " + res)
+		} else {
+			b.WriteString(" (" + res + ")")
+		}
+	}
+	return b.String()
+}
+
+// fieldTypes 把每個欄位的型別依名稱個數重複列出；keepNames 給型別參數用
+func fieldTypes(fl *ast.FieldList, keepNames bool) string {
+	var parts []string
+	for _, f := range fl.List {
+		t := types.ExprString(f.Type)
+		n := max(len(f.Names), 1)
+		for i := range n {
+			if keepNames && len(f.Names) > 0 {
+				parts = append(parts, f.Names[i].Name+" "+t)
+			} else {
+				parts = append(parts, t)
+			}
+		}
+	}
+	return strings.Join(parts, ", ")
+}
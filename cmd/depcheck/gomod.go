@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+)
+
+// dependency 是 go.mod 裡的一個 require，以及它可能的 replace
+type dependency struct {
+	Path     string
+	Version  string
+	Indirect bool
+	// Dir 不是空字串表示被 replace 到本地目錄，原始碼直接從這裡讀
+	Dir string
+}
+
+// readGoMod 讀 go.mod，回傳模組路徑和所有的 require
+func readGoMod(path string) (string, []dependency, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", nil, err
+	}
+	f, err := modfile.Parse(path, data, nil)
+	if err != nil {
+		return "", nil, err
+	}
+	if f.Module == nil {
+		return "", nil, fmt.Errorf("%s: no module line", path)
+	}
+
+	deps := make([]dependency, 0, len(f.Require))
+	for _, r := range f.Require {
+		deps = append(deps, dependency{Path: r.Mod.Path, Version: r.Mod.Version, Indirect: r.Indirect})
+	}
+
+	// 只有 replace 到本地路徑的需要處理；指定版本的 replace 比不指定的優先
+	for _, pass := range []bool{false, true} {
+		for _, r := range f.Replace {
+			if (r.Old.Version != "") != pass || r.New.Version != "" {
+				continue
+			}
+			dir := r.New.Path
+			if !filepath.IsAbs(dir) {
+				dir = filepath.Join(filepath.Dir(path), dir)
+			}
+			for i, d := range deps {
+				if d.Path == r.Old.Path && (r.Old.Version == "" || r.Old.Version == d.Version) {
+					deps[i].Dir = dir
+				}
+			}
+		}
+	}
+	return f.Module.Mod.Path, deps, nil
+}
+
+// readGoSum 回傳 go.sum 裡每個版本的 zip 雜湊。
+// 只有 /go.mod 那一行的版本也會列出來，雜湊是空字串。
+func readGoSum(path string) (map[module.Version]string, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return map[module.Version]string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sums := map[module.Version]string{}
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; sc.Scan(); n++ {
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("%s:%d: malformed line", path, n)
+		}
+		version, isMod := strings.CutSuffix(fields[1], "/go.mod")
+		mv := module.Version{Path: fields[0], Version: version}
+		if isMod {
+			if _, ok := sums[mv]; !ok {
+				sums[mv] = ""
+			}
+			continue
+		}
+		sums[mv] = fields[2]
+	}
+	return sums, sc.Err()
+}
@@ -0,0 +1,326 @@
+// Depcheck reports which exported identifiers this module uses from each
+// dependency, and whether they still exist with the same signature in the
+// other versions of that dependency found in the module cache.
+//
+// 升級 puppy 之前先跑一次，就知道 puppy.From13 或 BidBarks 會不會壞掉。
+// 完全離線：只讀 go.mod、go.sum 和 GOMODCACHE，不會下載任何東西。
+//
+// Usage:
+//
+//	go run ./cmd/depcheck [-C dir] [-modcache dir] [-tags list] [-allow-missing]
+//
+// 只檢查套件層級的識別字（pkg.Name），透過型別呼叫的方法不在檢查範圍內。
+// 每個識別字先列出 go.mod 指定版本的簽名，底下再列出跟它不一樣的版本：
+//
+//	MISSING      那個版本沒有這個識別字
+//	changed: …   那個版本的簽名不一樣
+//
+// go.sum 有記錄但快取裡沒有原始碼的版本會列在 not cached。
+//
+// 目前版本缺少任何用到的識別字，或 zip 的雜湊跟 go.sum 對不上時，結束碼是 1。
+// 快取裡沒有目前版本的原始碼時簽名會顯示 "?"，什麼都沒檢查到，所以結束碼也是 1，
+// 除非加上 -allow-missing。
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+var errNotCached = errors.New("not in the module cache")
+
+func main() {
+	dir := flag.String("C", ".", "module root containing go.mod and go.sum")
+	cache := flag.String("modcache", defaultModCache(), "module cache directory (GOMODCACHE)")
+	tags := flag.String("tags", "", "comma-separated build tags used when reading dependency sources")
+	allowMissing := flag.Bool("allow-missing", false, "exit 0 even if the required version of a dependency could not be read")
+	flag.Parse()
+
+	var tagList []string
+	if *tags != "" {
+		tagList = strings.Split(*tags, ",")
+	}
+	ok, err := run(os.Stdout, *dir, modCache{Dir: *cache}, tagList, *allowMissing)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "depcheck:", err)
+		os.Exit(2)
+	}
+	if !ok {
+		os.Exit(1)
+	}
+}
+
+func defaultModCache() string {
+	if d := os.Getenv("GOMODCACHE"); d != "" {
+		return d
+	}
+	gopath := os.Getenv("GOPATH")
+	if gopath == "" {
+		home, _ := os.UserHomeDir()
+		gopath = filepath.Join(home, "go")
+	}
+	return filepath.Join(filepath.SplitList(gopath)[0], "pkg", "mod")
+}
+
+// run 寫出報告，ok 為 false 表示目前的版本有問題，
+// 或是讀不到目前版本而 allowMissing 沒有設定
+func run(w io.Writer, root string, cache modCache, tags []string, allowMissing bool) (ok bool, err error) {
+	modPath, deps, err := readGoMod(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return false, err
+	}
+	sums, err := readGoSum(filepath.Join(root, "go.sum"))
+	if err != nil {
+		return false, err
+	}
+
+	// 先打開每個依賴目前版本的原始碼，用來查沒有別名的 import 的套件名稱
+	current := map[string]*source{}
+	currentErr := map[string]error{}
+	for _, d := range deps {
+		src, err := openDep(cache, d, sums)
+		if err != nil {
+			currentErr[d.Path] = err
+			continue
+		}
+		defer src.Close()
+		current[d.Path] = src
+	}
+
+	depOf := func(importPath string) (dependency, bool) {
+		// 最長的模組路徑優先，處理一個模組是另一個模組子目錄的情況
+		var best dependency
+		for _, d := range deps {
+			if (importPath == d.Path || strings.HasPrefix(importPath, d.Path+"/")) && len(d.Path) > len(best.Path) {
+				best = d
+			}
+		}
+		return best, best.Path != ""
+	}
+	isDep := func(ip string) bool {
+		_, ok := depOf(ip)
+		return ok && ip != modPath && !strings.HasPrefix(ip, modPath+"/")
+	}
+	pkgName := func(ip string) string {
+		d, _ := depOf(ip)
+		if src, ok := current[d.Path]; ok {
+			if a, err := loadAPI(src.FS, pkgDir(d.Path, ip), tags); err == nil {
+				return a.Name
+			}
+		}
+		return guessName(ip)
+	}
+
+	u, err := collectUses(root, isDep, pkgName)
+	if err != nil {
+		return false, err
+	}
+
+	ok = true
+	for _, d := range deps {
+		fmt.Fprintf(w, "%s %s", d.Path, d.Version)
+		if d.Indirect {
+			fmt.Fprint(w, " // indirect")
+		}
+		fmt.Fprintln(w)
+
+		var imports []string
+		for ip := range u {
+			if dd, _ := depOf(ip); dd.Path == d.Path {
+				imports = append(imports, ip)
+			}
+		}
+		slices.Sort(imports)
+		if len(imports) == 0 {
+			fmt.Fprint(w, "  not imported by this module\n\n")
+			continue
+		}
+
+		src, have := current[d.Path]
+		if have {
+			fmt.Fprintf(w, "  source: %s\n", src.From)
+			if src.Verified != "" {
+				fmt.Fprintf(w, "  go.sum: %s\n", src.Verified)
+				if src.Verified != "ok" {
+					ok = false
+				}
+			}
+		} else {
+			fmt.Fprintf(w, "  source: %v\n", currentErr[d.Path])
+		}
+
+		others := otherVersions(cache, d, sums)
+		for _, ip := range imports {
+			if !report(w, cache, d, ip, u[ip], src, others, sums, tags, allowMissing) {
+				ok = false
+			}
+		}
+		fmt.Fprintln(w)
+	}
+	return ok, nil
+}
+
+// report 寫出一個 import 路徑的表格，回傳目前版本是否有全部用到的識別字；
+// 讀不到目前版本時，除非 allowMissing，否則也回傳 false
+//
+// 版本可能很多（golang.org/x 的 pseudo-version 動輒幾十個），所以表格只列出目前的簽名，
+// 跟目前不一樣的版本才逐行列在識別字下面。
+func report(w io.Writer, cache modCache, d dependency, ip string, idents map[string][]string,
+	src *source, others []string, sums map[module.Version]string, tags []string, allowMissing bool) bool {
+
+	dir := pkgDir(d.Path, ip)
+	fmt.Fprintf(w, "  package %s\n", ip)
+
+	var cur *api
+	if src != nil {
+		var err error
+		if cur, err = loadAPI(src.FS, dir, tags); err != nil {
+			fmt.Fprintf(w, "    cannot read package at %s: %v\n", d.Version, err)
+		}
+	}
+
+	// 其他版本的 API；讀不到的版本記在 skipped
+	var (
+		versions []string
+		apis     []*api
+		skipped  = map[string][]string{}
+	)
+	for _, v := range others {
+		mv := module.Version{Path: d.Path, Version: v}
+		s, err := cache.open(mv, sums[mv])
+		if err != nil {
+			skipped["not cached"] = append(skipped["not cached"], v)
+			continue
+		}
+		a, err := loadAPI(s.FS, dir, tags)
+		s.Close()
+		if err != nil {
+			skipped["no package"] = append(skipped["no package"], v)
+			continue
+		}
+		versions = append(versions, v)
+		apis = append(apis, a)
+	}
+
+	names := make([]string, 0, len(idents))
+	for id := range idents {
+		names = append(names, id)
+	}
+	slices.Sort(names)
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "    IDENT\t%s\tUSED BY\n", d.Version)
+	ok := true
+	for _, id := range names {
+		curSig := "?"
+		if cur != nil {
+			var have bool
+			if curSig, have = cur.Decls[id]; !have {
+				curSig = "MISSING"
+				ok = false
+			}
+		}
+		fmt.Fprintf(tw, "    %s\t%s\t%s\n", id, curSig, strings.Join(idents[id], ", "))
+		for i, a := range apis {
+			if c := compare(curSig, a, id); c != "=" {
+				fmt.Fprintf(tw, "      %s\t%s\t\n", versions[i], c)
+			}
+		}
+	}
+	tw.Flush()
+
+	fmt.Fprintf(w, "    compared with %d other version(s)", len(versions))
+	for _, why := range []string{"not cached", "no package"} {
+		if vs := skipped[why]; len(vs) > 0 {
+			fmt.Fprintf(w, "; %s: %s", why, strings.Join(vs, ", "))
+		}
+	}
+	fmt.Fprintln(w)
+	if cur == nil {
+		if !allowMissing {
+			fmt.Fprintf(w, "    could not check %s at %s (use -allow-missing to ignore)\n", ip, d.Version)
+			return false
+		}
+		fmt.Fprintf(w, "    could not check %s at %s (ignored by -allow-missing)\n", ip, d.Version)
+	}
+	return ok
+}
+
+// compare 回傳 id 在另一個版本的狀態："="、"MISSING" 或 "changed: 新簽名"
+func compare(curSig string, a *api, id string) string {
+	sig, ok := a.Decls[id]
+	switch {
+	case !ok && curSig == "MISSING":
+		return "="
+	case !ok:
+		return "MISSING"
+	case sig == curSig:
+		return "="
+	default:
+		return "changed: " + sig
+	}
+}
+
+// openDep 打開依賴目前版本的原始碼：replace 到本地的用本地目錄，其餘找快取
+func openDep(cache modCache, d dependency, sums map[module.Version]string) (*source, error) {
+	if d.Dir != "" {
+		if _, err := os.Stat(d.Dir); err != nil {
+			return nil, err
+		}
+		return &source{FS: os.DirFS(d.Dir), From: d.Dir + " (replace)"}, nil
+	}
+	mv := module.Version{Path: d.Path, Version: d.Version}
+	src, err := cache.open(mv, sums[mv])
+	if errors.Is(err, errNotCached) {
+		return nil, fmt.Errorf("%s@%s: %w (run go mod download while online)", d.Path, d.Version, err)
+	}
+	return src, err
+}
+
+// otherVersions 回傳快取裡或 go.sum 裡除了目前版本以外的所有版本，由舊到新
+func otherVersions(cache modCache, d dependency, sums map[module.Version]string) []string {
+	vs := cache.versions(d.Path)
+	for mv := range sums {
+		if mv.Path == d.Path {
+			vs = append(vs, mv.Version)
+		}
+	}
+	semver.Sort(vs)
+	vs = slices.Compact(vs)
+	return slices.DeleteFunc(vs, func(v string) bool { return v == d.Version })
+}
+
+// pkgDir 回傳 import 路徑在模組裡的目錄，模組根目錄是 "."
+func pkgDir(modPath, importPath string) string {
+	rel := strings.TrimPrefix(strings.TrimPrefix(importPath, modPath), "/")
+	if rel == "" {
+		return "."
+	}
+	return path.Clean(rel)
+}
+
+// guessName 在讀不到原始碼時猜套件名稱：取最後一段，去掉 /v2 這種主版本和不合法的字元
+func guessName(importPath string) string {
+	name := path.Base(importPath)
+	if semver.IsValid(name) && semver.Major(name) == name && path.Dir(importPath) != "." {
+		name = path.Base(path.Dir(importPath))
+	}
+	name = strings.TrimPrefix(name, "go-")
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == '.' {
+			return -1
+		}
+		return r
+	}, name)
+}
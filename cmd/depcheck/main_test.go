@@ -0,0 +1,400 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/sumdb/dirhash"
+)
+
+// writeFiles 把 files 寫到 dir 底下，key 是用 / 分隔的相對路徑
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, body := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// writeZip 照 go 模組 zip 的格式寫出 mv 的原始碼，回傳 h1 雜湊
+func writeZip(t *testing.T, cache string, mv module.Version, files map[string]string) string {
+	t.Helper()
+	p := filepath.Join(cache, "cache", "download", filepath.FromSlash(mv.Path), "@v", mv.Version+".zip")
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Create(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	for name, body := range files {
+		w, err := zw.Create(mv.Path + "@" + mv.Version + "/" + name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(body))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	h, err := dirhash.HashZip(p, dirhash.Hash1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
+
+var (
+	pup12 = map[string]string{
+		"go.mod": "module example.com/go-pup\n",
+		"pup.go": "package pup\n\nfunc Bark() string { return \"\" }\n",
+	}
+	pup13 = map[string]string{
+		"go.mod": "module example.com/go-pup\n",
+		"pup.go": "package pup\n\nfunc Bark() string { return \"\" }\n\nfunc From13(s string) string { return s }\n",
+	}
+	pup14 = map[string]string{
+		"go.mod": "module example.com/go-pup\n",
+		"pup.go": "package pup\n\nfunc Bark(times int) string { return \"\" }\n\nfunc From13(name string) string { return name }\n",
+	}
+)
+
+// newModule 建立一個假的模組和模組快取：
+// go-pup v1.3.0 已解開，v1.2.0 少了 From13，v1.4.0 改了 Bark 的簽名，
+// example.com/local 被 replace 到本地目錄。
+func newModule(t *testing.T) (root string, cache modCache) {
+	t.Helper()
+	root, dir := t.TempDir(), t.TempDir()
+	cache = modCache{Dir: dir}
+
+	writeFiles(t, filepath.Join(dir, "example.com", "go-pup@v1.3.0"), pup13)
+	h13 := writeZip(t, dir, module.Version{Path: "example.com/go-pup", Version: "v1.3.0"}, pup13)
+	h12 := writeZip(t, dir, module.Version{Path: "example.com/go-pup", Version: "v1.2.0"}, pup12)
+	writeZip(t, dir, module.Version{Path: "example.com/go-pup", Version: "v1.4.0"}, pup14)
+
+	writeFiles(t, root, map[string]string{
+		"go.mod": `module example.com/app
+
+require (
+	example.com/go-pup v1.3.0
+	example.com/local v0.0.0
+	example.com/unused v0.1.0 // indirect
+)
+
+replace example.com/local => ./local
+`,
+		"go.sum": "example.com/go-pup v1.2.0 " + h12 + "\n" +
+			"example.com/go-pup v1.2.0/go.mod h1:x=\n" +
+			"example.com/go-pup v1.3.0 " + h13 + "\n" +
+			"example.com/go-pup v1.3.0/go.mod h1:x=\n" +
+			"example.com/unused v0.1.0/go.mod h1:x=\n",
+		"local/go.mod":   "module example.com/local\n",
+		"local/local.go": "package local\n\nfunc Desc() string { return \"\" }\n",
+		"a/a.go": `package a
+
+import (
+	"fmt"
+
+	"example.com/go-pup"
+	loc "example.com/local"
+)
+
+func A() {
+	fmt.Println(pup.Bark(), pup.From13("x"), loc.Desc())
+}
+`,
+		"b/b_test.go": `package b
+
+import "example.com/go-pup"
+
+func shadow(pup int) int { return pup }
+
+var _ = pup.Bark
+`,
+		"_skip/s.go":    "package s\n\nimport \"example.com/go-pup\"\n\nvar _ = pup.Gone\n",
+		"nested/go.mod": "module example.com/nested\n",
+		"nested/n.go":   "package n\n\nimport \"example.com/go-pup\"\n\nvar _ = pup.Gone\n",
+	})
+	return root, cache
+}
+
+func TestReadGoMod(t *testing.T) {
+	root, _ := newModule(t)
+	mod, deps, err := readGoMod(filepath.Join(root, "go.mod"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mod != "example.com/app" {
+		t.Error("Expected", "example.com/app", "Got", mod)
+	}
+	want := []dependency{
+		{Path: "example.com/go-pup", Version: "v1.3.0"},
+		{Path: "example.com/local", Version: "v0.0.0", Dir: filepath.Join(root, "local")},
+		{Path: "example.com/unused", Version: "v0.1.0", Indirect: true},
+	}
+	if !slices.Equal(deps, want) {
+		t.Error("Expected", want, "Got", deps)
+	}
+}
+
+func TestReadGoSum(t *testing.T) {
+	root, _ := newModule(t)
+	sums, err := readGoSum(filepath.Join(root, "go.sum"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sums) != 3 {
+		t.Error("Expected", 3, "Got", len(sums), sums)
+	}
+	if h := sums[module.Version{Path: "example.com/go-pup", Version: "v1.2.0"}]; !strings.HasPrefix(h, "h1:") {
+		t.Error("Expected a h1 hash Got", h)
+	}
+	// 只有 /go.mod 那一行的版本雜湊是空字串
+	if h, ok := sums[module.Version{Path: "example.com/unused", Version: "v0.1.0"}]; !ok || h != "" {
+		t.Error("Expected empty hash Got", h, ok)
+	}
+
+	if _, err := readGoSum(filepath.Join(root, "missing")); err != nil {
+		t.Error("a missing go.sum is not an error, got", err)
+	}
+	writeFiles(t, root, map[string]string{"bad.sum": "example.com/x v1.0.0\n"})
+	if _, err := readGoSum(filepath.Join(root, "bad.sum")); err == nil {
+		t.Error("Expected an error for a malformed line")
+	}
+}
+
+func TestModCache(t *testing.T) {
+	_, cache := newModule(t)
+
+	want := []string{"v1.2.0", "v1.3.0", "v1.4.0"}
+	if vs := cache.versions("example.com/go-pup"); !slices.Equal(vs, want) {
+		t.Error("Expected", want, "Got", vs)
+	}
+	if vs := cache.versions("example.com/nope"); len(vs) != 0 {
+		t.Error("Expected no versions Got", vs)
+	}
+
+	// 解開的目錄優先，雜湊還是用 zip 驗證
+	v13 := module.Version{Path: "example.com/go-pup", Version: "v1.3.0"}
+	h13, _ := dirhash.HashZip(filepath.Join(cache.Dir, "cache", "download", "example.com", "go-pup", "@v", "v1.3.0.zip"), dirhash.Hash1)
+	src, err := cache.open(v13, h13)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(src.From, "go-pup@v1.3.0") || src.Verified != "ok" {
+		t.Error("Expected extracted dir verified ok Got", src.From, src.Verified)
+	}
+	src.Close()
+
+	src, err = cache.open(v13, "h1:AAAA")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if src.Verified != "MISMATCH" {
+		t.Error("Expected", "MISMATCH", "Got", src.Verified)
+	}
+	src.Close()
+
+	// 沒有解開的版本從 zip 讀
+	src, err = cache.open(module.Version{Path: "example.com/go-pup", Version: "v1.2.0"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+	if !strings.HasSuffix(src.From, ".zip") || src.Verified != "" {
+		t.Error("Expected unverified zip Got", src.From, src.Verified)
+	}
+	a, err := loadAPI(src.FS, ".", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Name != "pup" || len(a.Decls) != 1 {
+		t.Error("Expected package pup with Bark only Got", a.Name, a.Decls)
+	}
+
+	if _, err := cache.open(module.Version{Path: "example.com/go-pup", Version: "v9.9.9"}, ""); err != errNotCached {
+		t.Error("Expected", errNotCached, "Got", err)
+	}
+}
+
+func TestCollectUses(t *testing.T) {
+	root, _ := newModule(t)
+	isDep := func(ip string) bool { return strings.HasPrefix(ip, "example.com/") }
+	pkgName := func(ip string) string {
+		if ip == "example.com/go-pup" {
+			return "pup"
+		}
+		return guessName(ip)
+	}
+	u, err := collectUses(root, isDep, pkgName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pup := u["example.com/go-pup"]
+	if got := pup["Bark"]; !slices.Equal(got, []string{"a", "b"}) {
+		t.Error("Expected", []string{"a", "b"}, "Got", got)
+	}
+	if got := pup["From13"]; !slices.Equal(got, []string{"a"}) {
+		t.Error("Expected", []string{"a"}, "Got", got)
+	}
+	// _skip 和 nested 模組不算；被參數遮住的 pup 也不算
+	if _, ok := pup["Gone"]; ok {
+		t.Error("Expected Gone to be ignored Got", pup)
+	}
+	if got := u["example.com/local"]["Desc"]; !slices.Equal(got, []string{"a"}) {
+		t.Error("Expected", []string{"a"}, "Got", got)
+	}
+}
+
+func TestLoadAPI(t *testing.T) {
+	fsys := os.DirFS(t.TempDir())
+	if _, err := loadAPI(fsys, ".", nil); err == nil {
+		t.Error("Expected an error for a directory without Go files")
+	}
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"p.go": `package p
+
+type T struct {
+	A, B int
+	c    string
+}
+
+const Max, min = 10, 1
+
+var Err error
+
+func New[K comparable](k K, n ...int) (*T, error) { return nil, nil }
+
+func (T) Method() {}
+
+func hidden() {}
+`,
+		"p_test.go":      "package p\n\nfunc Test() {}\n",
+		"p_windows.go":   "package p\n\nfunc Windows() {}\n",
+		"tagged.go":      "//go:build extra\n\npackage p\n\nfunc Extra() {}\n",
+		"sub/ignored.go": "package sub\n\nfunc Sub() {}\n",
+	})
+
+	a, err := loadAPI(os.DirFS(dir), ".", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]string{
+		"T":   "type struct{A, B int; c string}",
+		"Max": "const",
+		"Err": "var error",
+		"New": "func[K comparable](K, ...int) (*T, error)",
+	}
+	for name, sig := range tests {
+		if got := a.Decls[name]; got != sig {
+			t.Error(name, "Expected", sig, "Got", got)
+		}
+	}
+	for _, name := range []string{"min", "hidden", "Method", "Test", "Windows", "Extra", "Sub"} {
+		if _, ok := a.Decls[name]; ok {
+			t.Error("Expected", name, "to be excluded Got", a.Decls[name])
+		}
+	}
+
+	a, err = loadAPI(os.DirFS(dir), ".", []string{"extra"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := a.Decls["Extra"]; !ok {
+		t.Error("Expected Extra with -tags extra Got", a.Decls)
+	}
+}
+
+func TestGuessName(t *testing.T) {
+	tests := map[string]string{
+		"github.com/andyrestart9/private-repo": "privaterepo",
+		"example.com/go-pup":                   "pup",
+		"example.com/yaml.v3":                  "yamlv3",
+		"example.com/thing/v2":                 "thing",
+	}
+	for ip, want := range tests {
+		if got := guessName(ip); got != want {
+			t.Error(ip, "Expected", want, "Got", got)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	root, cache := newModule(t)
+	var buf bytes.Buffer
+	ok, err := run(&buf, root, cache, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Error("Expected ok, the required version has every identifier")
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"example.com/go-pup v1.3.0\n",
+		"go.sum: ok",
+		"v1.2.0  MISSING",
+		"v1.4.0  changed: func(int) string",
+		"compared with 2 other version(s)",
+		"(replace)",
+		"Desc   func() string  a",
+		"example.com/unused v0.1.0 // indirect\n  not imported by this module",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output does not contain %q:\n%s", want, out)
+		}
+	}
+	// From13 的參數名稱在 v1.4.0 改了，但簽名一樣
+	if strings.Contains(out, "changed: func(string) string") {
+		t.Error("parameter names should not count as a change:\n" + out)
+	}
+
+	// go.mod 要求的版本少了用到的識別字就不 ok
+	writeFiles(t, root, map[string]string{"a/more.go": "package a\n\nimport \"example.com/go-pup\"\n\nvar _ = pup.Gone\n"})
+	buf.Reset()
+	if ok, err := run(&buf, root, cache, nil, false); ok || err != nil {
+		t.Error("Expected not ok Got", ok, err)
+	}
+	if !strings.Contains(buf.String(), "Gone      MISSING") {
+		t.Error("Expected Gone to be MISSING:\n" + buf.String())
+	}
+}
+
+// 快取裡沒有目前版本時什麼都沒檢查到，不能當成 ok
+func TestRunNotCached(t *testing.T) {
+	root, cache := newModule(t)
+	os.RemoveAll(filepath.Join(cache.Dir, "example.com", "go-pup@v1.3.0"))
+	os.Remove(filepath.Join(cache.Dir, "cache", "download", "example.com", "go-pup", "@v", "v1.3.0.zip"))
+
+	var buf bytes.Buffer
+	if ok, err := run(&buf, root, cache, nil, false); ok || err != nil {
+		t.Error("Expected not ok Got", ok, err)
+	}
+	out := buf.String()
+	for _, want := range []string{"From13    ?", "could not check example.com/go-pup at v1.3.0"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output does not contain %q:\n%s", want, out)
+		}
+	}
+
+	buf.Reset()
+	if ok, err := run(&buf, root, cache, nil, true); !ok || err != nil {
+		t.Error("Expected ok with allowMissing Got", ok, err, "\n"+buf.String())
+	}
+}
@@ -5,6 +5,8 @@ go 1.23.6
 require (
 	github.com/andyrestart9/private-repo v0.0.0-20250215133011-b87f94999c98
 	github.com/andyrestart9/puppy v1.3.0
+	golang.org/x/exp v0.0.0-20250808145144-a408d31f581a
+	golang.org/x/mod v0.27.0
 )
 
 require github.com/andyrestart9/dog v0.0.0-20250215084519-3067746a3e23 // indirect